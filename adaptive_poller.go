@@ -0,0 +1,94 @@
+package transmissionrpc
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+/*
+	Adaptive polling
+	Built on torrent-get
+*/
+
+// AdaptivePollerConfig configures an AdaptivePoller.
+type AdaptivePollerConfig struct {
+	// Fields to retrieve. rateDownload and rateUpload are always requested as they drive the polling interval.
+	Fields []string
+	// IDs to poll, all torrents if empty.
+	IDs []int64
+	// MinInterval is used as long as at least one torrent is transferring data.
+	MinInterval time.Duration
+	// MaxInterval is the longest interval, reached when torrents stay idle (the interval doubles on each idle poll).
+	MaxInterval time.Duration
+	// OnError is called (if not nil) when a poll fails. Polling continues with the current interval.
+	OnError func(err error)
+}
+
+// AdaptivePoller polls the torrents and sends them on the returned channel. The interval between polls is reset to
+// MinInterval as soon as a torrent is transferring data (download or upload) and doubles on each poll where all
+// the torrents are idle, up to MaxInterval. Sending waits for the consumer: slow consumers slow down polling.
+// The channel is closed once ctx is cancelled.
+func (c *Client) AdaptivePoller(ctx context.Context, cfg AdaptivePollerConfig) (torrents <-chan []Torrent, err error) {
+	// Validate
+	if cfg.MinInterval <= 0 {
+		err = errors.New("min interval must be positive")
+		return
+	}
+	if cfg.MaxInterval < cfg.MinInterval {
+		err = errors.New("max interval can't be lower than min interval")
+		return
+	}
+	if err = c.validateTorrentFields(cfg.Fields); err != nil {
+		return
+	}
+	cfg.Fields = withTorrentField(withTorrentField(cfg.Fields, "rateDownload"), "rateUpload")
+	// Start polling
+	output := make(chan []Torrent)
+	go c.adaptivePoll(ctx, cfg, output)
+	torrents = output
+	return
+}
+
+func (c *Client) adaptivePoll(ctx context.Context, cfg AdaptivePollerConfig, output chan<- []Torrent) {
+	defer close(output)
+	interval := cfg.MinInterval
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return
+		}
+		torrents, err := c.fetchTorrents(ctx, cfg.Fields, cfg.IDs)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if cfg.OnError != nil {
+				cfg.OnError(err)
+			}
+		} else {
+			select {
+			case output <- torrents:
+			case <-ctx.Done():
+				return
+			}
+			interval = nextPollInterval(interval, torrents, cfg.MinInterval, cfg.MaxInterval)
+		}
+		timer.Reset(interval)
+	}
+}
+
+func nextPollInterval(current time.Duration, torrents []Torrent, min, max time.Duration) time.Duration {
+	for _, torrent := range torrents {
+		if (torrent.RateDownload != nil && *torrent.RateDownload > 0) || (torrent.RateUpload != nil && *torrent.RateUpload > 0) {
+			return min
+		}
+	}
+	if current *= 2; current > max {
+		current = max
+	}
+	return current
+}
@@ -0,0 +1,167 @@
+package transmissionrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+/*
+	Alternative speed scheduling
+	Helpers built on the alt-speed-* session arguments
+	https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#41-session-arguments
+*/
+
+// AltSpeedDay is a bitmask of days used by the daemon to schedule alternative speed limits (tr_sched_day).
+type AltSpeedDay int64
+
+const (
+	// AltSpeedSunday represents sunday
+	AltSpeedSunday AltSpeedDay = 1 << iota
+	// AltSpeedMonday represents monday
+	AltSpeedMonday
+	// AltSpeedTuesday represents tuesday
+	AltSpeedTuesday
+	// AltSpeedWednesday represents wednesday
+	AltSpeedWednesday
+	// AltSpeedThursday represents thursday
+	AltSpeedThursday
+	// AltSpeedFriday represents friday
+	AltSpeedFriday
+	// AltSpeedSaturday represents saturday
+	AltSpeedSaturday
+)
+
+const (
+	// AltSpeedWeekdays represents monday to friday
+	AltSpeedWeekdays = AltSpeedMonday | AltSpeedTuesday | AltSpeedWednesday | AltSpeedThursday | AltSpeedFriday
+	// AltSpeedWeekend represents saturday and sunday
+	AltSpeedWeekend = AltSpeedSaturday | AltSpeedSunday
+	// AltSpeedEveryday represents all the days of the week
+	AltSpeedEveryday = AltSpeedWeekdays | AltSpeedWeekend
+)
+
+// Has returns true if day is part of the mask.
+func (asd AltSpeedDay) Has(day time.Weekday) bool {
+	return asd&(1<<uint(day)) != 0
+}
+
+// ScheduleWindow is a daily time window during which the alternative speed limits are active.
+// Begin and End are offsets from midnight and must be within [0, 24h). If End is before Begin, the window
+// spans midnight: it starts on the selected days and ends on the following day.
+type ScheduleWindow struct {
+	Days  AltSpeedDay
+	Begin time.Duration
+	End   time.Duration
+}
+
+// Schedule describes when the daemon should switch to its alternative speed limits ("quiet hours") and what those limits are.
+type Schedule struct {
+	Windows       []ScheduleWindow
+	DownloadLimit int64 // alternative max global download speed (KBps)
+	UploadLimit   int64 // alternative max global upload speed (KBps)
+}
+
+// SessionArguments converts the schedule into the session arguments configuring the daemon native scheduler.
+// As the daemon only knows a single begin/end time of day, all the windows must share the same Begin
+// and End (only their days can differ).
+func (s Schedule) SessionArguments() (payload SessionArguments, err error) {
+	if len(s.Windows) == 0 {
+		err = errors.New("schedule must have at least one window")
+		return
+	}
+	if s.DownloadLimit < 0 || s.UploadLimit < 0 {
+		err = errors.New("schedule speed limits can't be negative")
+		return
+	}
+	var days AltSpeedDay
+	for index, window := range s.Windows {
+		if err = window.validate(); err != nil {
+			err = fmt.Errorf("window %d is invalid: %w", index, err)
+			return
+		}
+		if window.Begin.Truncate(time.Minute) != s.Windows[0].Begin.Truncate(time.Minute) ||
+			window.End.Truncate(time.Minute) != s.Windows[0].End.Truncate(time.Minute) {
+			err = fmt.Errorf("window %d does not share the begin and end times of the first window: the daemon only supports one time range", index)
+			return
+		}
+		days |= window.Days
+	}
+	enabled := true
+	begin := int64(s.Windows[0].Begin / time.Minute)
+	end := int64(s.Windows[0].End / time.Minute)
+	daysMask := int64(days)
+	down := s.DownloadLimit
+	up := s.UploadLimit
+	payload = SessionArguments{
+		AltSpeedDown:        &down,
+		AltSpeedTimeBegin:   &begin,
+		AltSpeedTimeDay:     &daysMask,
+		AltSpeedTimeEnabled: &enabled,
+		AltSpeedTimeEnd:     &end,
+		AltSpeedUp:          &up,
+	}
+	return
+}
+
+func (sw ScheduleWindow) validate() error {
+	if sw.Days == 0 || sw.Days&^AltSpeedEveryday != 0 {
+		return fmt.Errorf("invalid days mask %d", sw.Days)
+	}
+	if sw.Begin < 0 || sw.Begin >= 24*time.Hour {
+		return fmt.Errorf("begin %v is not within a day", sw.Begin)
+	}
+	if sw.End < 0 || sw.End >= 24*time.Hour {
+		return fmt.Errorf("end %v is not within a day (use an end before begin to span midnight)", sw.End)
+	}
+	if sw.Begin.Truncate(time.Minute) == sw.End.Truncate(time.Minute) {
+		return errors.New("begin and end can't be the same minute of the day")
+	}
+	return nil
+}
+
+// ApplyBandwidthSchedule configures the daemon native alternative speed scheduler (turtle mode) to match schedule.
+func (c *Client) ApplyBandwidthSchedule(ctx context.Context, schedule Schedule) (err error) {
+	payload, err := schedule.SessionArguments()
+	if err != nil {
+		err = fmt.Errorf("invalid schedule: %w", err)
+		return
+	}
+	return c.SessionArgumentsSet(ctx, payload)
+}
+
+// AltSpeedScheduledAt returns true if the alternative speed scheduler described by sessionArgs (alt-speed-time-*
+// arguments) puts the daemon in turtle mode at t. t must be expressed in the daemon time zone.
+func AltSpeedScheduledAt(sessionArgs SessionArguments, t time.Time) bool {
+	if sessionArgs.AltSpeedTimeEnabled == nil || !*sessionArgs.AltSpeedTimeEnabled ||
+		sessionArgs.AltSpeedTimeBegin == nil || sessionArgs.AltSpeedTimeEnd == nil || sessionArgs.AltSpeedTimeDay == nil {
+		return false
+	}
+	days := AltSpeedDay(*sessionArgs.AltSpeedTimeDay)
+	begin, end := *sessionArgs.AltSpeedTimeBegin, *sessionArgs.AltSpeedTimeEnd
+	minute := int64(t.Hour()*60 + t.Minute())
+	switch {
+	case begin < end:
+		return days.Has(t.Weekday()) && begin <= minute && minute < end
+	case begin > end:
+		// spans midnight: started today or the day before
+		return (days.Has(t.Weekday()) && minute >= begin) || (days.Has((t.Weekday()+6)%7) && minute < end)
+	default:
+		return false
+	}
+}
+
+// IsAltSpeedActive returns true if the alternative speed limits (turtle mode) are in force right now: either enabled
+// (alt-speed-enabled) or scheduled for the current time (see AltSpeedScheduledAt). The daemon is assumed to share
+// the local time zone.
+func (c *Client) IsAltSpeedActive(ctx context.Context) (active bool, err error) {
+	sessionArgs, err := c.SessionArgumentsGet(ctx, []string{
+		"alt-speed-enabled", "alt-speed-time-enabled", "alt-speed-time-begin", "alt-speed-time-end", "alt-speed-time-day",
+	})
+	if err != nil {
+		return
+	}
+	active = (sessionArgs.AltSpeedEnabled != nil && *sessionArgs.AltSpeedEnabled) || AltSpeedScheduledAt(sessionArgs, time.Now())
+	return
+}
@@ -0,0 +1,184 @@
+package transmissionrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+	Audit log
+	Structured trail of the mutating calls, see Config.AuditLog
+*/
+
+// readOnlyMethods are the RPC methods not reported to the audit log.
+var readOnlyMethods = map[string]bool{
+	MethodTorrentGet:   true,
+	MethodSessionGet:   true,
+	MethodSessionStats: true,
+	MethodPortTest:     true,
+	MethodFreeSpace:    true,
+	MethodGroupGet:     true,
+}
+
+// auditRedactedArguments are never reported in the audit log summaries.
+var auditRedactedArguments = map[string]bool{
+	"cookies":      true,
+	"rpc-password": true,
+	"rpc-username": true,
+}
+
+// auditURLArguments may carry private URLs (passkeys in the announce URLs or .torrent download links): only the scheme
+// and host of their URLs are reported.
+var auditURLArguments = map[string]bool{
+	"filename":       true,
+	"trackerAdd":     true,
+	"trackerList":    true,
+	"trackerReplace": true,
+}
+
+const auditMaxValueLength = 128
+
+// AuditEntry describes a mutating call (every call but torrent-get, session-get, session-stats, port-test, free-space
+// and group-get) sent through the client, see Config.AuditLog.
+type AuditEntry struct {
+	Time   time.Time // when the call was sent
+	Method string
+	// IDs and Hashes are the targeted torrents, both empty if the call does not target torrents or targets all of them.
+	// Selector is set instead for the "recently-active" selector.
+	IDs      []int64
+	Hashes   []string
+	Selector string
+	// Summary lists the other arguments as "key=value" sorted by key. Secrets (cookies, rpc credentials) are redacted,
+	// only the scheme and host of the tracker and .torrent URLs are kept (and the info hash of the magnet links),
+	// .torrent contents are replaced by their size and long values are truncated.
+	Summary string
+	// Err is the error of the call, nil if it succeeded.
+	Err error
+}
+
+func (c *Client) audit(start time.Time, method string, arguments interface{}, err error) {
+	entry := AuditEntry{
+		Time:   start,
+		Method: method,
+		Err:    err,
+	}
+	if arguments != nil {
+		entry.decodeArguments(arguments)
+	}
+	c.settings.AuditLog(entry)
+}
+
+func (ae *AuditEntry) decodeArguments(arguments interface{}) {
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		ae.Summary = fmt.Sprintf("<arguments can't be encoded: %s>", err)
+		return
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if key == "ids" {
+			ae.decodeIDs(fields[key])
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	summary := make([]string, len(keys))
+	for index, key := range keys {
+		value := string(fields[key])
+		switch {
+		case auditRedactedArguments[key]:
+			value = "<redacted>"
+		case key == "metainfo":
+			value = fmt.Sprintf("<%d base64 bytes>", len(fields[key])-2)
+		case auditURLArguments[key]:
+			value = redactAuditURLs(fields[key])
+			if len(value) > auditMaxValueLength {
+				value = value[:auditMaxValueLength] + "..."
+			}
+		case len(value) > auditMaxValueLength:
+			value = value[:auditMaxValueLength] + "..."
+		}
+		summary[index] = key + "=" + value
+	}
+	ae.Summary = strings.Join(summary, ", ")
+}
+
+func (ae *AuditEntry) decodeIDs(raw json.RawMessage) {
+	var selector TorrentSelector
+	if err := json.Unmarshal(raw, &selector); err != nil {
+		return
+	}
+	if selector.recentlyActive {
+		ae.Selector = recentlyActiveSelector
+		return
+	}
+	ae.IDs = selector.ids
+	ae.Hashes = selector.hashes
+}
+
+// redactAuditURLs returns the JSON value raw with its URLs (within strings, possibly one per line) redacted.
+func redactAuditURLs(raw json.RawMessage) string {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "<redacted>"
+	}
+	var encoded strings.Builder
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(redactAuditValue(value)); err != nil {
+		return "<redacted>"
+	}
+	return strings.TrimSuffix(encoded.String(), "\n")
+}
+
+func redactAuditValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case string:
+		lines := strings.Split(typed, "\n")
+		for index, line := range lines {
+			lines[index] = redactAuditURL(line)
+		}
+		return strings.Join(lines, "\n")
+	case []interface{}:
+		for index, item := range typed {
+			typed[index] = redactAuditValue(item)
+		}
+	}
+	return value
+}
+
+// redactAuditURL keeps the scheme and host of an URL (the info hashes of a magnet link). Other values (local paths)
+// are kept as is.
+func redactAuditURL(value string) string {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil || len(parsed.Scheme) <= 1 { // no scheme or a windows drive letter
+		return value
+	}
+	if parsed.Scheme == "magnet" {
+		redacted := "magnet:?"
+		for index, xt := range parsed.Query()["xt"] {
+			if index > 0 {
+				redacted += "&"
+			}
+			redacted += "xt=" + xt
+		}
+		return redacted
+	}
+	if parsed.Host == "" {
+		return "<redacted>"
+	}
+	redacted := parsed.Scheme + "://" + parsed.Host
+	if parsed.Path != "" || parsed.RawQuery != "" || parsed.User != nil {
+		redacted += "/<redacted>"
+	}
+	return redacted
+}
@@ -0,0 +1,127 @@
+package transmissionrpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+/*
+	Backup manifest
+	Lightweight backup and restore of the torrents built on magnet links
+*/
+
+// ManifestEntry describes a torrent enough to add it back from its magnet link, see BackupManifest().
+type ManifestEntry struct {
+	HashString    string   `json:"hashString"`
+	MagnetLink    string   `json:"magnetLink"`
+	Name          string   `json:"name"`
+	DownloadDir   string   `json:"downloadDir"`
+	Labels        []string `json:"labels,omitempty"`
+	UnwantedFiles []int64  `json:"unwantedFiles,omitempty"` // indices of the files not downloaded
+	// Incomplete flags the entries which may not be restorable: their magnet link has no tracker, their metadata
+	// will then only be retrieved if DHT or PEX peers have it (never for private torrents).
+	Incomplete bool   `json:"incomplete,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// BackupManifest returns a manifest of all the torrents which can be serialized (to JSON for example) and restored
+// with RestoreFromManifest(), without keeping the .torrent files.
+func (c *Client) BackupManifest(ctx context.Context) (manifest []ManifestEntry, err error) {
+	torrents, err := c.torrentGet(ctx, []string{"id", "hashString", "magnetLink", "name", "downloadDir", "labels", "wanted", "isPrivate"}, nil)
+	if err != nil {
+		return
+	}
+	manifest = make([]ManifestEntry, 0, len(torrents))
+	for _, torrent := range torrents {
+		entry := ManifestEntry{
+			HashString:  deref(torrent.HashString),
+			MagnetLink:  deref(torrent.MagnetLink),
+			Name:        deref(torrent.Name),
+			DownloadDir: deref(torrent.DownloadDir),
+			Labels:      torrent.Labels,
+		}
+		for index, wanted := range torrent.Wanted {
+			if !wanted {
+				entry.UnwantedFiles = append(entry.UnwantedFiles, int64(index))
+			}
+		}
+		switch {
+		case entry.MagnetLink == "":
+			entry.Incomplete, entry.Reason = true, "no magnet link"
+		case !strings.Contains(entry.MagnetLink, "&tr="):
+			entry.Incomplete = true
+			if deref(torrent.IsPrivate) {
+				entry.Reason = "private torrent magnet link without tracker: metadata can not be retrieved"
+			} else {
+				entry.Reason = "magnet link without tracker: metadata depends on DHT/PEX peers"
+			}
+		}
+		manifest = append(manifest, entry)
+	}
+	return
+}
+
+// RestoreResult is the outcome of the restoration of one manifest entry, see RestoreFromManifest().
+type RestoreResult struct {
+	Entry   ManifestEntry
+	Torrent Torrent // as returned by TorrentAdd()
+	Err     error
+	// SelectionPending is true when the entry has unwanted files but the torrent metadata is not retrieved
+	// yet: the selection must be applied later (TorrentSet FilesUnwanted) once the metadata is complete.
+	SelectionPending bool
+}
+
+// RestoreFromManifest adds back the torrents of a manifest (see BackupManifest()) from their magnet links to their
+// download dirs with their labels. The files selection is applied if the metadata is already known (torrent already
+// present for example). Entries without magnet link are reported as failed; restoring continues after a failure.
+// err is only set if ctx is done, in which case the remaining entries are not restored.
+func (c *Client) RestoreFromManifest(ctx context.Context, manifest []ManifestEntry) (results []RestoreResult, err error) {
+	results = make([]RestoreResult, 0, len(manifest))
+	for _, entry := range manifest {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		results = append(results, c.restoreManifestEntry(ctx, entry))
+	}
+	return
+}
+
+func (c *Client) restoreManifestEntry(ctx context.Context, entry ManifestEntry) (result RestoreResult) {
+	result.Entry = entry
+	if entry.MagnetLink == "" {
+		result.Err = fmt.Errorf("torrent '%s' has no magnet link", entry.HashString)
+		return
+	}
+	payload := TorrentAddPayload{
+		Filename: &entry.MagnetLink,
+		Labels:   entry.Labels,
+	}
+	if entry.DownloadDir != "" {
+		payload.DownloadDir = &entry.DownloadDir
+	}
+	if result.Torrent, result.Err = c.TorrentAdd(ctx, payload); result.Err != nil {
+		return
+	}
+	if len(entry.UnwantedFiles) == 0 {
+		return
+	}
+	// Files selection needs the metadata
+	if result.Torrent.ID == nil {
+		result.SelectionPending = true
+		return
+	}
+	var current Torrent
+	if current, result.Err = c.torrentGetOne(ctx, []string{"id", "metadataPercentComplete"}, *result.Torrent.ID); result.Err != nil {
+		return
+	}
+	if current.MetadataPercentComplete == nil || *current.MetadataPercentComplete < 1 {
+		result.SelectionPending = true
+		return
+	}
+	result.Err = c.TorrentSet(ctx, TorrentSetPayload{
+		IDs:           []int64{*result.Torrent.ID},
+		FilesUnwanted: entry.UnwantedFiles,
+	})
+	return
+}
@@ -14,7 +14,7 @@ import (
 
 // BandwidthGroup represents all possible fields of data for a bandwidth group.
 type BandwidthGroup struct {
-	HonorSessionLimits    bool   `json:"honorSessionLimits"`
+	HonorSessionLimits    bool   `json:"honorsSessionLimits"`
 	Name                  string `json:"name"`
 	SpeedLimitDownEnabled bool   `json:"speed-limit-down-enabled"`
 	SpeedLimitDown        int64  `json:"speed-limit-down"`
@@ -34,7 +34,7 @@ func (c *Client) BandwidthGroupGet(ctx context.Context, groups []string) (bandwi
 	if len(groups) > 0 {
 		filter = strings.Join(groups, ",")
 	}
-	if err = c.rpcCall(ctx, "group-get", &bandwidthGroupGetParams{
+	if err = c.rpcCall(ctx, MethodGroupGet, &bandwidthGroupGetParams{
 		Group: filter,
 	}, &answer); err != nil {
 		err = fmt.Errorf("'group-get' rpc method failed: %w", err)
@@ -59,8 +59,97 @@ func (c *Client) BandwidthGroupSet(ctx context.Context, bwGroup BandwidthGroup)
 		return errors.New("Bandwidth group must have a name")
 	}
 	// Send payload
-	if err = c.rpcCall(ctx, "group-set", bwGroup, nil); err != nil {
+	if err = c.rpcCall(ctx, MethodGroupSet, bwGroup, nil); err != nil {
 		err = fmt.Errorf("'group-set' rpc method failed: %w", err)
 	}
 	return
 }
+
+// Group is a bandwidth group as returned by GroupGet(), its fields mirror GroupSetPayload.
+type Group struct {
+	Name                  string `json:"name"`
+	HonorsSessionLimits   bool   `json:"honorsSessionLimits"`      // true if session upload limits are honored
+	SpeedLimitDownEnabled bool   `json:"speed-limit-down-enabled"` // true means enabled
+	SpeedLimitDown        int64  `json:"speed-limit-down"`         // max download speed (KBps)
+	SpeedLimitUpEnabled   bool   `json:"speed-limit-up-enabled"`   // true means enabled
+	SpeedLimitUp          int64  `json:"speed-limit-up"`           // max upload speed (KBps)
+}
+
+// GroupGet returns the bandwidth groups matching names (all of them if names is empty). As for BandwidthGroupGet(),
+// the daemon may ignore the filter and return all the groups.
+func (c *Client) GroupGet(ctx context.Context, names []string) (groups []Group, err error) {
+	var answer groupGetAnswer
+	if err = c.rpcCall(ctx, MethodGroupGet, &bandwidthGroupGetParams{
+		Group: strings.Join(names, ","),
+	}, &answer); err != nil {
+		err = fmt.Errorf("'group-get' rpc method failed: %w", err)
+		return
+	}
+	groups = answer.Group
+	return
+}
+
+type groupGetAnswer struct {
+	Group []Group `json:"group"`
+}
+
+// GroupSetPayload holds the mutators of a bandwidth group (created if it does not exist yet). Name is mandatory,
+// nil fields are left untouched (unlike BandwidthGroupSet() which sends all the fields).
+type GroupSetPayload struct {
+	Name                  string `json:"name"`
+	HonorsSessionLimits   *bool  `json:"honorsSessionLimits,omitempty"`      // true if session upload limits are honored
+	SpeedLimitDownEnabled *bool  `json:"speed-limit-down-enabled,omitempty"` // true means enabled
+	SpeedLimitDown        *int64 `json:"speed-limit-down,omitempty"`         // max download speed (KBps)
+	SpeedLimitUpEnabled   *bool  `json:"speed-limit-up-enabled,omitempty"`   // true means enabled
+	SpeedLimitUp          *int64 `json:"speed-limit-up,omitempty"`           // max upload speed (KBps)
+}
+
+// GroupSet applies the non nil mutators of payload to its bandwidth group.
+func (c *Client) GroupSet(ctx context.Context, payload GroupSetPayload) (err error) {
+	// Validate
+	if payload.Name == "" {
+		return errors.New("bandwidth group must have a name")
+	}
+	if (payload.SpeedLimitDown != nil && *payload.SpeedLimitDown < 0) || (payload.SpeedLimitUp != nil && *payload.SpeedLimitUp < 0) {
+		return errors.New("bandwidth group speed limits can't be negative")
+	}
+	// Send payload
+	if err = c.rpcCall(ctx, MethodGroupSet, payload, nil); err != nil {
+		err = fmt.Errorf("'group-set' rpc method failed: %w", err)
+	}
+	return
+}
+
+// GroupDetail is a bandwidth group along with the ids of its member torrents, see BandwidthGroupDetails().
+type GroupDetail struct {
+	BandwidthGroup
+	TorrentIDs []int64
+}
+
+// BandwidthGroupDetails returns all the bandwidth groups with their member torrents, in 2 calls (group-get and torrent-get).
+// Torrents without group or referencing a group unknown to group-get are not reported.
+func (c *Client) BandwidthGroupDetails(ctx context.Context) (details []GroupDetail, err error) {
+	groups, err := c.BandwidthGroupGet(ctx, nil)
+	if err != nil {
+		return
+	}
+	torrents, err := c.torrentGet(ctx, []string{"id", "group"}, nil)
+	if err != nil {
+		return
+	}
+	details = make([]GroupDetail, len(groups))
+	byName := make(map[string]int, len(groups))
+	for index, group := range groups {
+		details[index].BandwidthGroup = group
+		byName[group.Name] = index
+	}
+	for _, torrent := range torrents {
+		if torrent.ID == nil || torrent.Group == nil {
+			continue
+		}
+		if index, known := byName[*torrent.Group]; known {
+			details[index].TorrentIDs = append(details[index].TorrentIDs, *torrent.ID)
+		}
+	}
+	return
+}
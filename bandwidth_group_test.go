@@ -0,0 +1,41 @@
+package transmissionrpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestGroupSetReadBackByGroupGet(t *testing.T) {
+	var stored map[string]interface{}
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		switch rq.Method {
+		case MethodGroupSet:
+			if err := json.Unmarshal(rq.Arguments, &stored); err != nil {
+				t.Errorf("can't decode group-set arguments: %v", err)
+			}
+			writeStubAnswer(t, w, rq, nil)
+		case MethodGroupGet:
+			writeStubAnswer(t, w, rq, map[string]interface{}{"group": []interface{}{stored}})
+		default:
+			t.Errorf("unexpected method %q", rq.Method)
+		}
+	})
+	honors, limit := true, int64(512)
+	if err := client.GroupSet(context.Background(), GroupSetPayload{
+		Name:                "slow",
+		HonorsSessionLimits: &honors,
+		SpeedLimitDown:      &limit,
+	}); err != nil {
+		t.Fatalf("GroupSet() failed: %v", err)
+	}
+	groups, err := client.GroupGet(context.Background(), []string{"slow"})
+	if err != nil {
+		t.Fatalf("GroupGet() failed: %v", err)
+	}
+	if len(groups) != 1 || groups[0].Name != "slow" || !groups[0].HonorsSessionLimits || groups[0].SpeedLimitDown != limit {
+		t.Errorf("got %+v; want the values written by GroupSet()", groups)
+	}
+}
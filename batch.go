@@ -0,0 +1,179 @@
+package transmissionrpc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+/*
+	Write batching
+	Coalesce mutators and actions into fewer RPC calls
+*/
+
+const defaultBatchParallelism = 4
+
+// Batch accumulates mutators and actions and sends them with the fewest possible RPC calls: actions of the same method
+// are merged into a single call and torrent-set payloads carrying the same mutators are merged into a single payload.
+// It must be created with Client.NewBatch() and sent with Commit(). Operations are independent: they are sent
+// concurrently without any ordering guarantee (use a Sequence when order matters).
+type Batch struct {
+	client      *Client
+	access      sync.Mutex
+	parallelism int
+	sets        []TorrentSetPayload
+	setKeys     []string
+	actions     map[string][]int64
+	actionsSeen map[string]map[int64]bool
+}
+
+// NewBatch returns an empty batch bound to the client, sending up to 4 calls at once (see SetParallelism()).
+func (c *Client) NewBatch() *Batch {
+	return &Batch{
+		client:      c,
+		parallelism: defaultBatchParallelism,
+		actions:     make(map[string][]int64),
+		actionsSeen: make(map[string]map[int64]bool),
+	}
+}
+
+// SetParallelism sets the maximum number of calls Commit() sends at once (minimum 1).
+// The client limits (see Config.MaxConcurrentRPC) still apply.
+func (b *Batch) SetParallelism(parallelism int) *Batch {
+	defer b.access.Unlock()
+	b.access.Lock()
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	b.parallelism = parallelism
+	return b
+}
+
+// TorrentSet adds a torrent-set payload. Its ids are merged into an already added payload carrying exactly the same
+// mutators if any. Payloads using a Selector are never merged.
+func (b *Batch) TorrentSet(payload TorrentSetPayload) *Batch {
+	defer b.access.Unlock()
+	b.access.Lock()
+	key := ""
+	if payload.Selector == nil {
+		mutators := payload
+		mutators.IDs = nil
+		if encoded, err := json.Marshal(mutators); err == nil {
+			key = string(encoded)
+		}
+	}
+	if key != "" {
+		for index, setKey := range b.setKeys {
+			if setKey == key {
+				b.sets[index].IDs = append(b.sets[index].IDs, payload.IDs...)
+				return b
+			}
+		}
+	}
+	payload.IDs = append([]int64(nil), payload.IDs...)
+	b.sets = append(b.sets, payload)
+	b.setKeys = append(b.setKeys, key)
+	return b
+}
+
+func (b *Batch) action(method string, ids []int64) *Batch {
+	defer b.access.Unlock()
+	b.access.Lock()
+	seen := b.actionsSeen[method]
+	if seen == nil {
+		seen = make(map[int64]bool, len(ids))
+		b.actionsSeen[method] = seen
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			b.actions[method] = append(b.actions[method], id)
+		}
+	}
+	return b
+}
+
+// TorrentStart adds ids to the batched torrent-start. Without ids nothing is added (not all torrents).
+func (b *Batch) TorrentStart(ids ...int64) *Batch {
+	return b.action(MethodTorrentStart, ids)
+}
+
+// TorrentStartNow adds ids to the batched torrent-start-now. Without ids nothing is added (not all torrents).
+func (b *Batch) TorrentStartNow(ids ...int64) *Batch {
+	return b.action(MethodTorrentStartNow, ids)
+}
+
+// TorrentStop adds ids to the batched torrent-stop. Without ids nothing is added (not all torrents).
+func (b *Batch) TorrentStop(ids ...int64) *Batch {
+	return b.action(MethodTorrentStop, ids)
+}
+
+// TorrentVerify adds ids to the batched torrent-verify. Without ids nothing is added (not all torrents).
+func (b *Batch) TorrentVerify(ids ...int64) *Batch {
+	return b.action(MethodTorrentVerify, ids)
+}
+
+// TorrentReannounce adds ids to the batched torrent-reannounce. Without ids nothing is added (not all torrents).
+func (b *Batch) TorrentReannounce(ids ...int64) *Batch {
+	return b.action(MethodTorrentReannounce, ids)
+}
+
+// Commit sends the batched operations and empties the batch. Failed calls are returned together as a MultiError
+// (indexed in the sending order: the torrent-set payloads first, then the actions).
+func (b *Batch) Commit(ctx context.Context) (err error) {
+	// Take the pending operations
+	b.access.Lock()
+	type batchOp struct {
+		method string
+		ids    []int64
+		fx     func(ctx context.Context) error
+	}
+	ops := make([]batchOp, 0, len(b.sets)+len(b.actions))
+	for _, payload := range b.sets {
+		payload := payload
+		ops = append(ops, batchOp{
+			method: MethodTorrentSet,
+			ids:    payload.IDs,
+			fx:     func(ctx context.Context) error { return b.client.TorrentSet(ctx, payload) },
+		})
+	}
+	for _, method := range []string{MethodTorrentStart, MethodTorrentStartNow, MethodTorrentStop, MethodTorrentVerify, MethodTorrentReannounce} {
+		ids := b.actions[method]
+		if len(ids) == 0 {
+			continue
+		}
+		method := method
+		ops = append(ops, batchOp{
+			method: method,
+			ids:    ids,
+			fx:     func(ctx context.Context) error { return b.client.torrentActionSelector(ctx, method, IDs(ids...)) },
+		})
+	}
+	parallelism := b.parallelism
+	b.sets, b.setKeys = nil, nil
+	b.actions = make(map[string][]int64)
+	b.actionsSeen = make(map[string]map[int64]bool)
+	b.access.Unlock()
+	// Send them
+	errs := make([]error, len(ops))
+	slots := make(chan struct{}, parallelism)
+	var workers sync.WaitGroup
+	workers.Add(len(ops))
+	for index := range ops {
+		slots <- struct{}{}
+		go func(index int) {
+			defer func() {
+				<-slots
+				workers.Done()
+			}()
+			if errs[index] = ctx.Err(); errs[index] == nil {
+				errs[index] = ops[index].fx(ctx)
+			}
+		}(index)
+	}
+	workers.Wait()
+	err = newMultiError(errs, func(index int) (method string, ids []int64) {
+		return ops[index].method, ops[index].ids
+	})
+	return
+}
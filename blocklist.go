@@ -2,6 +2,7 @@ package transmissionrpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 )
 
@@ -14,7 +15,7 @@ import (
 func (c *Client) BlocklistUpdate(ctx context.Context) (nbEntries int64, err error) {
 	var answer blocklistUpdateAnswer
 	// Send request
-	if err = c.rpcCall(ctx, "blocklist-update", nil, &answer); err == nil {
+	if err = c.rpcCall(ctx, MethodBlocklistUpdate, nil, &answer); err == nil {
 		nbEntries = answer.NbEntries
 	} else {
 		err = fmt.Errorf("'blocklist-update' rpc method failed: %w", err)
@@ -22,6 +23,20 @@ func (c *Client) BlocklistUpdate(ctx context.Context) (nbEntries int64, err erro
 	return
 }
 
+// BlocklistSize returns the number of entries of the current blocklist, read from session-get without updating it.
+func (c *Client) BlocklistSize(ctx context.Context) (nbEntries int64, err error) {
+	sessionArgs, err := c.SessionArgumentsGet(ctx, []string{"blocklist-size"})
+	if err != nil {
+		return
+	}
+	if sessionArgs.BlocklistSize == nil {
+		err = errors.New("payload blocklist size is nil")
+		return
+	}
+	nbEntries = *sessionArgs.BlocklistSize
+	return
+}
+
 type blocklistUpdateAnswer struct {
 	NbEntries int64 `json:"blocklist-size"`
 }
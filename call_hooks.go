@@ -0,0 +1,85 @@
+package transmissionrpc
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+/*
+	Call hooks
+	Instrumentation of each RPC attempt, see Config.Hooks
+*/
+
+// CallInfo describes an RPC attempt to the hooks. Duration, sizes and Err are only set for CallHooks.AfterCall.
+type CallInfo struct {
+	Method       string
+	Attempt      int           // starting at 1, retries (see Config.Retry) increase it
+	Duration     time.Duration // of the attempt, waits for the rate limiter and the free slots included
+	RequestSize  int64         // bytes of the JSON request body (0 if the request could not be built)
+	ResponseSize int64         // bytes of the answer body read
+	Err          error
+}
+
+// CallHooks are called around each RPC attempt, for example to record metrics or tracing spans. They are called
+// synchronously by the calling goroutine and must be safe for concurrent use if the client is shared.
+type CallHooks struct {
+	// BeforeCall (optional) is called before each attempt. The context it returns (if not nil) is used for the attempt
+	// and passed to AfterCall, allowing to carry a span for example.
+	BeforeCall func(ctx context.Context, info CallInfo) context.Context
+	// AfterCall (optional) is called after each attempt, successful or not.
+	AfterCall func(ctx context.Context, info CallInfo)
+}
+
+type callStatsKey struct{}
+
+// callStats collects the sizes of an attempt for the hooks, request() fills it when it is found within the context.
+type callStats struct {
+	requestSize  int64
+	responseSize int64
+}
+
+func callStatsFromContext(ctx context.Context) (stats *callStats) {
+	stats, _ = ctx.Value(callStatsKey{}).(*callStats)
+	return
+}
+
+// callAttempt sends one attempt of a call, surrounded by the hooks if any.
+func (c *Client) callAttempt(ctx context.Context, method string, attempt int, arguments interface{}, result interface{}) (err error) {
+	hooks := c.settings.Hooks
+	if hooks == nil || (hooks.BeforeCall == nil && hooks.AfterCall == nil) {
+		return asRPCError(method, c.call(ctx, method, arguments, result))
+	}
+	info := CallInfo{
+		Method:  method,
+		Attempt: attempt,
+	}
+	if hooks.BeforeCall != nil {
+		if hooked := hooks.BeforeCall(ctx, info); hooked != nil {
+			ctx = hooked
+		}
+	}
+	stats := &callStats{}
+	start := time.Now()
+	err = asRPCError(method, c.call(context.WithValue(ctx, callStatsKey{}, stats), method, arguments, result))
+	if hooks.AfterCall != nil {
+		info.Duration = time.Since(start)
+		info.RequestSize = stats.requestSize
+		info.ResponseSize = stats.responseSize
+		info.Err = err
+		hooks.AfterCall(ctx, info)
+	}
+	return
+}
+
+// countingReader counts the bytes read from an answer body.
+type countingReader struct {
+	reader io.Reader
+	count  *int64
+}
+
+func (cr countingReader) Read(p []byte) (n int, err error) {
+	n, err = cr.reader.Read(p)
+	*cr.count += int64(n)
+	return
+}
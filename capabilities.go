@@ -0,0 +1,242 @@
+package transmissionrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+/*
+	Server capabilities
+	RPC version negotiation and gating of the fields the server does not know
+    https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#5-protocol-versions
+*/
+
+// ServerCapabilities describes the remote server, see Client.ServerCapabilities().
+type ServerCapabilities struct {
+	RPCVersion        int64
+	RPCVersionMinimum int64
+	Version           string // long version string "$version ($revision)"
+	Features          FeatureSet
+}
+
+// SupportsTorrentField returns true if the server knows a torrent-get field.
+func (sc ServerCapabilities) SupportsTorrentField(field string) bool {
+	return sc.RPCVersion >= torrentFieldsMinRPCVersion[field]
+}
+
+// ServerCapabilities returns the capabilities of the remote server. They are fetched with session-get on first call
+// and cached afterwards, until the session id changes (the daemon may have been restarted or upgraded). Concurrent calls
+// wait for a single fetch, or for their context to be done.
+func (c *Client) ServerCapabilities(ctx context.Context) (capabilities ServerCapabilities, err error) {
+	for {
+		c.serverVersion.access.Lock()
+		if c.serverVersion.capabilities != nil {
+			capabilities = *c.serverVersion.capabilities
+			c.serverVersion.access.Unlock()
+			return
+		}
+		if c.serverVersion.fetching == nil {
+			fetching := make(chan struct{})
+			c.serverVersion.fetching = fetching
+			generation := c.serverVersion.generation
+			c.serverVersion.access.Unlock()
+			capabilities, err = c.fetchServerCapabilities(ctx)
+			c.serverVersion.access.Lock()
+			if err == nil && generation == c.serverVersion.generation {
+				c.serverVersion.capabilities = &capabilities
+			}
+			c.serverVersion.fetching = nil
+			close(fetching)
+			c.serverVersion.access.Unlock()
+			return
+		}
+		pending := c.serverVersion.fetching
+		c.serverVersion.access.Unlock()
+		select {
+		case <-pending:
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+	}
+}
+
+func (c *Client) fetchServerCapabilities(ctx context.Context) (capabilities ServerCapabilities, err error) {
+	sessionArgs, err := c.SessionArgumentsGet(ctx, []string{"rpc-version", "rpc-version-minimum", "version"})
+	if err != nil {
+		err = fmt.Errorf("can't get server RPC version: %w", err)
+		return
+	}
+	if sessionArgs.RPCVersion == nil {
+		err = errors.New("payload RPC Version is nil")
+		return
+	}
+	capabilities = ServerCapabilities{
+		RPCVersion:        *sessionArgs.RPCVersion,
+		RPCVersionMinimum: deref(sessionArgs.RPCVersionMinimum),
+		Version:           deref(sessionArgs.Version),
+		Features:          FeaturesForRPCVersion(*sessionArgs.RPCVersion),
+	}
+	return
+}
+
+// UnsupportedFieldsPolicy is what the client does with the fields the server RPC version does not know, see Config.UnsupportedFields.
+type UnsupportedFieldsPolicy int
+
+const (
+	// UnsupportedFieldsSend sends the fields as is: the server ignores them or refuses the call (default)
+	UnsupportedFieldsSend UnsupportedFieldsPolicy = iota
+	// UnsupportedFieldsStrip removes the fields from the requests before sending them
+	UnsupportedFieldsStrip
+	// UnsupportedFieldsReject refuses to send the requests, with a FieldUnsupportedError
+	UnsupportedFieldsReject
+)
+
+// ErrFieldUnsupported is matched (with errors.Is) by the FieldUnsupportedError errors.
+var ErrFieldUnsupported = errors.New("field not supported by the server RPC version")
+
+// FieldUnsupportedError is returned when a request uses a field the server RPC version does not know
+// and Config.UnsupportedFields is UnsupportedFieldsReject.
+type FieldUnsupportedError struct {
+	Field         string
+	MinVersion    int64 // RPC version the field appeared in
+	ServerVersion int64
+}
+
+func (fue FieldUnsupportedError) Error() string {
+	return fmt.Sprintf("field '%s' requires RPC v%d but the server is RPC v%d", fue.Field, fue.MinVersion, fue.ServerVersion)
+}
+
+// Is allows to match ErrFieldUnsupported.
+func (fue FieldUnsupportedError) Is(target error) bool {
+	return target == ErrFieldUnsupported
+}
+
+// ErrUnsupportedRPCVersion is matched (with errors.Is) by the errors of the methods the server RPC version does not know.
+var ErrUnsupportedRPCVersion = errors.New("method not supported by the server RPC version")
+
+// requireRPCVersion returns an error matching ErrUnsupportedRPCVersion if the server is older than minVersion.
+func (c *Client) requireRPCVersion(ctx context.Context, method string, minVersion int64) (err error) {
+	capabilities, err := c.ServerCapabilities(ctx)
+	if err != nil {
+		return
+	}
+	if capabilities.RPCVersion < minVersion {
+		err = fmt.Errorf("%w: '%s' requires RPC v%d but the server is RPC v%d", ErrUnsupportedRPCVersion, method,
+			minVersion, capabilities.RPCVersion)
+	}
+	return
+}
+
+// torrentSetMinRPCVersion lists the torrent-set arguments which are not available on every RPC version.
+var torrentSetMinRPCVersion = map[string]int64{
+	"group":               17,
+	"labels":              16,
+	"sequential_download": 18,
+	"trackerList":         17,
+}
+
+// torrentAddMinRPCVersion lists the torrent-add arguments which are not available on every RPC version.
+var torrentAddMinRPCVersion = map[string]int64{
+	"labels": 17,
+}
+
+// unsupportedFields returns, according to the client policy, the fields (among the ones set) to strip from a request.
+// An error is returned instead if the policy is to reject them. The server version is only fetched if needed.
+func (c *Client) unsupportedFields(ctx context.Context, set []string, minVersions map[string]int64) (strip map[string]bool, err error) {
+	if c.settings.UnsupportedFields == UnsupportedFieldsSend {
+		return
+	}
+	versioned := false
+	for _, field := range set {
+		if _, versioned = minVersions[field]; versioned {
+			break
+		}
+	}
+	if !versioned {
+		return
+	}
+	capabilities, err := c.ServerCapabilities(ctx)
+	if err != nil {
+		return
+	}
+	for _, field := range set {
+		minVersion := minVersions[field]
+		if capabilities.RPCVersion >= minVersion {
+			continue
+		}
+		if c.settings.UnsupportedFields == UnsupportedFieldsReject {
+			return nil, FieldUnsupportedError{Field: field, MinVersion: minVersion, ServerVersion: capabilities.RPCVersion}
+		}
+		if strip == nil {
+			strip = make(map[string]bool)
+		}
+		strip[field] = true
+	}
+	return
+}
+
+// gateTorrentSet applies the unsupported fields policy to a torrent-set payload.
+func (c *Client) gateTorrentSet(ctx context.Context, payload *TorrentSetPayload) (err error) {
+	var set []string
+	if payload.Group != nil {
+		set = append(set, "group")
+	}
+	if payload.Labels != nil {
+		set = append(set, "labels")
+	}
+	if payload.SequentialDownload != nil {
+		set = append(set, "sequential_download")
+	}
+	if payload.TrackerList != nil {
+		set = append(set, "trackerList")
+	}
+	strip, err := c.unsupportedFields(ctx, set, torrentSetMinRPCVersion)
+	if err != nil {
+		return
+	}
+	if strip["group"] {
+		payload.Group = nil
+	}
+	if strip["labels"] {
+		payload.Labels = nil
+	}
+	if strip["sequential_download"] {
+		payload.SequentialDownload = nil
+	}
+	if strip["trackerList"] {
+		payload.TrackerList = nil
+	}
+	return
+}
+
+// gateTorrentAdd applies the unsupported fields policy to a torrent-add payload.
+func (c *Client) gateTorrentAdd(ctx context.Context, payload *TorrentAddPayload) (err error) {
+	if payload.Labels == nil {
+		return
+	}
+	strip, err := c.unsupportedFields(ctx, []string{"labels"}, torrentAddMinRPCVersion)
+	if err != nil {
+		return
+	}
+	if strip["labels"] {
+		payload.Labels = nil
+	}
+	return
+}
+
+// gateTorrentGet applies the unsupported fields policy to the fields of a torrent-get.
+func (c *Client) gateTorrentGet(ctx context.Context, fields []string) (gated []string, err error) {
+	strip, err := c.unsupportedFields(ctx, fields, torrentFieldsMinRPCVersion)
+	if err != nil || len(strip) == 0 {
+		return fields, err
+	}
+	gated = make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !strip[field] {
+			gated = append(gated, field)
+		}
+	}
+	return
+}
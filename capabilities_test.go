@@ -0,0 +1,141 @@
+package transmissionrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// rpcVersionHandler answers session-get with rpcVersion and records the port-test calls.
+func rpcVersionHandler(t *testing.T, rpcVersion int64, portTests *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		switch rq.Method {
+		case MethodSessionGet:
+			writeStubAnswer(t, w, rq, map[string]interface{}{"rpc-version": rpcVersion, "version": "4.1.0"})
+		case MethodPortTest:
+			*portTests++
+			var arguments portTestPayload
+			if err := json.Unmarshal(rq.Arguments, &arguments); err != nil {
+				t.Errorf("can't decode port-test arguments: %v", err)
+			}
+			writeStubAnswer(t, w, rq, map[string]interface{}{"port-is-open": true, "ipProtocol": arguments.IPProtocol})
+		default:
+			t.Errorf("unexpected method '%s'", rq.Method)
+		}
+	}
+}
+
+func TestPortTestProtocolRequiresRPCv18(t *testing.T) {
+	var portTests int
+	client := newStubClient(t, nil, rpcVersionHandler(t, 17, &portTests))
+	if _, err := client.PortTestProtocol(context.Background(), PortTestIPv6); !errors.Is(err, ErrUnsupportedRPCVersion) {
+		t.Errorf("got error %v, want ErrUnsupportedRPCVersion", err)
+	}
+	if portTests != 0 {
+		t.Errorf("got %d port-test requests, want none", portTests)
+	}
+	client = newStubClient(t, nil, rpcVersionHandler(t, 18, &portTests))
+	open, err := client.PortTestProtocol(context.Background(), PortTestIPv6)
+	if err != nil || !open {
+		t.Errorf("got %v, %v; want an open port", open, err)
+	}
+	if portTests != 1 {
+		t.Errorf("got %d port-test requests, want 1", portTests)
+	}
+}
+
+func TestServerCapabilitiesWaitCancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	var fetches int32
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		writeStubAnswer(t, w, rq, map[string]interface{}{"rpc-version": 17})
+	})
+	go func() {
+		_, _ = client.ServerCapabilities(context.Background()) // blocked fetch
+	}()
+	for atomic.LoadInt32(&fetches) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.ServerCapabilities(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want the context error", err)
+	}
+	if fetches := atomic.LoadInt32(&fetches); fetches != 1 {
+		t.Errorf("got %d session-get, want a single fetch", fetches)
+	}
+}
+
+func TestServerCapabilitiesResetOnSessionIDChange(t *testing.T) {
+	var conflicts, fetches int32
+	var session, version atomic.Value
+	session.Store("first")
+	version.Store(int64(17))
+	client := newStubClient(t, nil, sessionIDHandler(t, func() string { return session.Load().(string) }, &conflicts,
+		func(w http.ResponseWriter, r *http.Request) {
+			rq := readStubRequest(t, r)
+			if rq.Method == MethodSessionGet {
+				atomic.AddInt32(&fetches, 1)
+			}
+			writeStubAnswer(t, w, rq, map[string]interface{}{"rpc-version": version.Load()})
+		}))
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if capabilities, err := client.ServerCapabilities(ctx); err != nil || capabilities.RPCVersion != 17 {
+			t.Fatalf("got RPC v%d (%v), want v17", capabilities.RPCVersion, err)
+		}
+	}
+	if fetches := atomic.LoadInt32(&fetches); fetches != 1 {
+		t.Errorf("got %d session-get, want the capabilities cached", fetches)
+	}
+	// Daemon upgraded and restarted
+	session.Store("second")
+	version.Store(int64(18))
+	if _, err := client.SessionStats(ctx); err != nil {
+		t.Fatalf("SessionStats() failed: %v", err)
+	}
+	if capabilities, err := client.ServerCapabilities(ctx); err != nil || capabilities.RPCVersion != 18 {
+		t.Errorf("got RPC v%d (%v) after the session id change, want v18", capabilities.RPCVersion, err)
+	}
+}
+
+func TestTorrentGetIterDropsUnsupportedFields(t *testing.T) {
+	client := newStubClient(t, &Config{UnsupportedFields: UnsupportedFieldsStrip}, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		switch rq.Method {
+		case MethodSessionGet:
+			writeStubAnswer(t, w, rq, map[string]interface{}{"rpc-version": 16})
+		case MethodTorrentGet:
+			var arguments torrentGetParams
+			if err := json.Unmarshal(rq.Arguments, &arguments); err != nil {
+				t.Errorf("can't decode torrent-get arguments: %v", err)
+			}
+			for _, field := range arguments.Fields {
+				if field == "group" {
+					t.Error("field 'group' (RPC v17) requested from a RPC v16 daemon")
+				}
+			}
+			writeStubAnswer(t, w, rq, map[string]interface{}{"torrents": []interface{}{map[string]interface{}{"id": 1}}})
+		default:
+			t.Errorf("unexpected method '%s'", rq.Method)
+		}
+	})
+	torrents, err := client.TorrentGetIter(context.Background(), []string{"name", "group"}, []int64{1}, 0)
+	if err != nil {
+		t.Fatalf("TorrentGetIter() failed: %v", err)
+	}
+	for item := range torrents {
+		if item.Err != nil {
+			t.Errorf("iteration failed: %v", item.Err)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package transmissionrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+/*
+	Change streaming
+	Built on torrent-get with the "recently-active" ids shortcut, full resync when its window may have been missed
+	https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#33-torrent-accessor-torrent-get
+*/
+
+// ChangeBatch holds the changes detected by one poll of a ChangeStream.
+type ChangeBatch struct {
+	Changed []Torrent // torrents added or with at least one requested field changed
+	Removed []int64   // ids of the torrents removed from the daemon
+	Err     error     // set if the poll failed, Changed and Removed are then empty
+}
+
+// recentlyActiveResync is how old the last successful poll can be before the next one fetches all the torrents again:
+// the daemon "recently-active" window (and its removed ids) only covers the last 60 seconds.
+const recentlyActiveResync = 45 * time.Second
+
+// ChangeStream polls the daemon every interval and sends on the returned channel the torrents whose requested
+// fields changed (and the ones removed) since the last poll. The first batch contains all the torrents.
+// The "id" field is always requested. Unchanged torrents reported by the daemon as recently active are skipped.
+// If coalesce is false, polling blocks until the previous batch has been received. If coalesce is true, polling
+// continues and undelivered changes are merged together until the consumer catches up, so no change is lost: poll
+// errors are then delivered in their own batches, after the pending changes (only the last undelivered one is kept).
+// Polls fetch the "recently-active" torrents only, unless the previous poll failed or the last successful one is
+// older than the daemon window (a slow consumer or an interval of 45s or more): all the torrents are then fetched
+// again and compared to the snapshot, the missing ones being reported as removed.
+// The channel is closed once ctx is cancelled.
+func (c *Client) ChangeStream(ctx context.Context, fields []string, interval time.Duration, coalesce bool) (batches <-chan ChangeBatch, err error) {
+	if interval <= 0 {
+		err = errors.New("interval must be positive")
+		return
+	}
+	if err = c.validateTorrentFields(fields); err != nil {
+		return
+	}
+	fields = withTorrentField(fields, "id")
+	output := make(chan ChangeBatch)
+	go c.changeStream(ctx, fields, interval, coalesce, output)
+	batches = output
+	return
+}
+
+func (c *Client) changeStream(ctx context.Context, fields []string, interval time.Duration, coalesce bool, output chan<- ChangeBatch) {
+	defer close(output)
+	var (
+		snapshot   = make(map[int64][]byte)
+		pending    *ChangeBatch
+		pendingErr error
+		batch      ChangeBatch
+		ticker     = time.NewTicker(interval)
+		resync     = true
+		synced     time.Time // start of the last successful poll
+		polled     time.Time
+		torrents   []Torrent
+		removed    []int64
+		err        error
+	)
+	defer ticker.Stop()
+	for {
+		// Poll
+		polled = time.Now()
+		if resync = resync || polled.Sub(synced) >= recentlyActiveResync; resync {
+			if torrents, err = c.fetchTorrents(ctx, fields, nil); err == nil {
+				removed = missingTorrents(snapshot, torrents)
+			}
+		} else {
+			torrents, removed, err = c.torrentGetRecentlyActive(ctx, fields)
+		}
+		if err != nil {
+			resync = true
+			batch = ChangeBatch{Err: err}
+		} else {
+			resync, synced = false, polled
+			batch = diffChanges(snapshot, torrents, removed)
+		}
+		// Deliver
+		if coalesce {
+			if batch.Err != nil {
+				pendingErr = batch.Err
+			} else if len(batch.Changed) > 0 || len(batch.Removed) > 0 {
+				pending = mergeChangeBatches(pending, batch)
+			}
+		} else if batch.Err != nil || len(batch.Changed) > 0 || len(batch.Removed) > 0 {
+			select {
+			case output <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+		// Wait for next poll (while trying to deliver pending changes first, then the pending error)
+		for waiting := true; waiting; {
+			var (
+				deliver chan<- ChangeBatch // nil (disabled) if nothing is pending
+				next    ChangeBatch
+			)
+			switch {
+			case pending != nil:
+				deliver, next = output, *pending
+			case pendingErr != nil:
+				deliver, next = output, ChangeBatch{Err: pendingErr}
+			}
+			select {
+			case deliver <- next:
+				if pending != nil {
+					pending = nil
+				} else {
+					pendingErr = nil
+				}
+			case <-ticker.C:
+				waiting = false
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// diffChanges updates snapshot with the torrents received and returns the effective changes.
+func diffChanges(snapshot map[int64][]byte, torrents []Torrent, removed []int64) (batch ChangeBatch) {
+	for _, torrent := range torrents {
+		if torrent.ID == nil {
+			continue
+		}
+		encoded, err := json.Marshal(torrent)
+		if err != nil {
+			// can't compare: consider it changed
+			batch.Changed = append(batch.Changed, torrent)
+			continue
+		}
+		if previous, known := snapshot[*torrent.ID]; known && bytes.Equal(previous, encoded) {
+			continue
+		}
+		snapshot[*torrent.ID] = encoded
+		batch.Changed = append(batch.Changed, torrent)
+	}
+	for _, id := range removed {
+		if _, known := snapshot[id]; known {
+			delete(snapshot, id)
+			batch.Removed = append(batch.Removed, id)
+		}
+	}
+	return
+}
+
+// missingTorrents returns the ids of the snapshot which are not within torrents (a full torrent list).
+func missingTorrents(snapshot map[int64][]byte, torrents []Torrent) (missing []int64) {
+	present := make(map[int64]bool, len(torrents))
+	for _, torrent := range torrents {
+		if torrent.ID != nil {
+			present[*torrent.ID] = true
+		}
+	}
+	for id := range snapshot {
+		if !present[id] {
+			missing = append(missing, id)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+	return
+}
+
+// mergeChangeBatches merges next into pending (both without error): latest torrent values win and removals cancel previous changes.
+func mergeChangeBatches(pending *ChangeBatch, next ChangeBatch) *ChangeBatch {
+	if pending == nil {
+		return &next
+	}
+	var merged ChangeBatch
+	removed := make(map[int64]bool, len(next.Removed))
+	for _, id := range next.Removed {
+		removed[id] = true
+	}
+	updated := make(map[int64]bool, len(next.Changed))
+	for _, torrent := range next.Changed {
+		updated[*torrent.ID] = true
+	}
+	for _, torrent := range pending.Changed {
+		if !removed[*torrent.ID] && !updated[*torrent.ID] {
+			merged.Changed = append(merged.Changed, torrent)
+		}
+	}
+	merged.Changed = append(merged.Changed, next.Changed...)
+	for _, id := range pending.Removed {
+		if !updated[id] && !removed[id] {
+			merged.Removed = append(merged.Removed, id)
+		}
+	}
+	merged.Removed = append(merged.Removed, next.Removed...)
+	return &merged
+}
+
+func (c *Client) torrentGetRecentlyActive(ctx context.Context, fields []string) (torrents []Torrent, removed []int64, err error) {
+	var result torrentGetRecentlyActiveResults
+	if err = c.rpcCall(ctx, MethodTorrentGet, &torrentGetRecentlyActiveParams{
+		Fields: fields,
+		IDs:    "recently-active",
+	}, &result); err != nil {
+		err = fmt.Errorf("'torrent-get' rpc method failed: %w", err)
+		return
+	}
+	torrents = result.Torrents
+	removed = result.Removed
+	return
+}
+
+// withTorrentField returns fields with field added if it was not already present.
+func withTorrentField(fields []string, field string) []string {
+	for _, f := range fields {
+		if f == field {
+			return fields
+		}
+	}
+	return append(append(make([]string, 0, len(fields)+1), fields...), field)
+}
+
+type torrentGetRecentlyActiveParams struct {
+	Fields []string `json:"fields"`
+	IDs    string   `json:"ids"`
+}
+
+type torrentGetRecentlyActiveResults struct {
+	Torrents []Torrent `json:"torrents"`
+	Removed  []int64   `json:"removed"`
+}
@@ -0,0 +1,120 @@
+package transmissionrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+/*
+	Advanced constructor
+*/
+
+const defaultRPCPath = "/transmission/rpc"
+
+// Advanced holds the transport settings of a client in a single place, see NewClientAdvanced().
+type Advanced struct {
+	// Host (mandatory unless UnixSocket is set) and Port of the daemon. Port defaults to 9091.
+	Host string
+	Port uint16
+	// UnixSocket (optional) is the path of a Unix domain socket to connect to instead of Host and Port (which then
+	// only fill the Host header, Host defaulting to localhost). It is ignored if Transport or CustomClient is provided.
+	UnixSocket string
+	// User and Password (optional) for the basic authentication.
+	User     string
+	Password string
+	// HTTPS enables TLS. RootCAs (optional) replaces the system pool to verify the daemon certificate,
+	// it is ignored if CustomClient is provided.
+	HTTPS   bool
+	RootCAs *x509.CertPool
+	// RPCPath defaults to /transmission/rpc.
+	RPCPath string
+	// Headers (optional) are added to each request.
+	Headers http.Header
+	// Timeout of each HTTP request, zero means no timeout. It is ignored if CustomClient is provided.
+	Timeout time.Duration
+	// Transport (optional) is used instead of the default pooled transport, for example to reach the daemon through
+	// a custom dialer. It is ignored if CustomClient is provided.
+	Transport http.RoundTripper
+	// CustomClient (optional) is used as is instead of the default pooled client.
+	CustomClient *http.Client
+	// RateLimit (requests per second) and RateLimitBurst (optional) cap the rate of RPC calls, for a client shared
+	// by many goroutines not to overwhelm the daemon. They override the ones of Extra when set, see Config.RateLimit.
+	RateLimit      float64
+	RateLimitBurst int
+	// Extra (optional) holds the other client options. Its CustomClient and Headers are overridden by the ones above.
+	Extra *Config
+}
+
+// NewClientAdvanced returns an initialized and ready to use client built from advanced transport settings.
+func NewClientAdvanced(advanced Advanced) (c *Client, err error) {
+	if advanced.Host == "" {
+		if advanced.UnixSocket == "" {
+			err = errors.New("host can not be empty")
+			return
+		}
+		advanced.Host = "localhost"
+	}
+	// Build the endpoint
+	if advanced.Port == 0 {
+		advanced.Port = 9091
+	}
+	if advanced.RPCPath == "" {
+		advanced.RPCPath = defaultRPCPath
+	}
+	endpoint := &url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(advanced.Host, strconv.Itoa(int(advanced.Port))),
+		Path:   advanced.RPCPath,
+	}
+	if advanced.HTTPS {
+		endpoint.Scheme = "https"
+	}
+	if advanced.User != "" || advanced.Password != "" {
+		endpoint.User = url.UserPassword(advanced.User, advanced.Password)
+	}
+	// Build the config
+	var extra Config
+	if advanced.Extra != nil {
+		extra = *advanced.Extra
+	}
+	extra.Headers = advanced.Headers
+	if advanced.RateLimit > 0 {
+		extra.RateLimit = advanced.RateLimit
+		extra.RateLimitBurst = advanced.RateLimitBurst
+	}
+	switch {
+	case advanced.CustomClient != nil:
+		extra.CustomClient = advanced.CustomClient
+	case advanced.Transport != nil:
+		extra.CustomClient = &http.Client{
+			Transport: advanced.Transport,
+			Timeout:   advanced.Timeout,
+		}
+	default:
+		extra.CustomClient = newPooledClient(&extra)
+		extra.CustomClient.Timeout = advanced.Timeout
+		if transport, ok := extra.CustomClient.Transport.(*http.Transport); ok {
+			if advanced.RootCAs != nil {
+				if transport.TLSClientConfig == nil {
+					transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+				}
+				transport.TLSClientConfig.RootCAs = advanced.RootCAs
+			}
+			if advanced.UnixSocket != "" {
+				socket := advanced.UnixSocket
+				transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socket)
+				}
+			}
+		}
+	}
+	return New(endpoint, &extra)
+}
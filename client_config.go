@@ -0,0 +1,118 @@
+package transmissionrpc
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+/*
+	Client configuration export/import
+*/
+
+// ClientConfig is the non secret configuration of a client, see Client.Config() and NewClientFromConfig().
+// It can be safely serialized: credentials are not part of it. A Config.CustomClient can not be exported,
+// only its timeout is.
+type ClientConfig struct {
+	Scheme                  string                  `json:"scheme"`
+	Host                    string                  `json:"host"`
+	Port                    string                  `json:"port,omitempty"`
+	Path                    string                  `json:"path"`
+	Timeout                 time.Duration           `json:"timeout,omitempty"`
+	UserAgent               string                  `json:"user_agent,omitempty"`
+	KeepLastRawResponse     bool                    `json:"keep_last_raw_response,omitempty"`
+	SessionCacheTTL         time.Duration           `json:"session_cache_ttl,omitempty"`
+	MaxIdleConns            int                     `json:"max_idle_conns,omitempty"`
+	MaxIdleConnsPerHost     int                     `json:"max_idle_conns_per_host,omitempty"`
+	IdleConnTimeout         time.Duration           `json:"idle_conn_timeout,omitempty"`
+	AllowPrivateTrackerEdit bool                    `json:"allow_private_tracker_edit,omitempty"`
+	RateLimit               float64                 `json:"rate_limit,omitempty"`
+	RateLimitBurst          int                     `json:"rate_limit_burst,omitempty"`
+	MaxConcurrentRPC        int                     `json:"max_concurrent_rpc,omitempty"`
+	MaxRetryAfter           time.Duration           `json:"max_retry_after,omitempty"`
+	MaxTorrentSetBatch      int                     `json:"max_torrent_set_batch,omitempty"`
+	RoundSeedIdleLimit      bool                    `json:"round_seed_idle_limit,omitempty"`
+	ReconnectOnDisconnect   bool                    `json:"reconnect_on_disconnect,omitempty"`
+	AddDefaults             *AddDefaults            `json:"add_defaults,omitempty"`
+	RejectDuplicateTorrents bool                    `json:"reject_duplicate_torrents,omitempty"`
+	UnsupportedFields       UnsupportedFieldsPolicy `json:"unsupported_fields,omitempty"`
+}
+
+// Config returns the non secret configuration of the client, which can be used with NewClientFromConfig().
+func (c *Client) Config() (config ClientConfig) {
+	config = ClientConfig{
+		Scheme:                  c.endpoint.Scheme,
+		Host:                    c.endpoint.Hostname(),
+		Port:                    c.endpoint.Port(),
+		Path:                    c.endpoint.Path,
+		UserAgent:               c.settings.UserAgent,
+		KeepLastRawResponse:     c.settings.KeepLastRawResponse,
+		SessionCacheTTL:         c.settings.SessionCacheTTL,
+		MaxIdleConns:            c.settings.MaxIdleConns,
+		MaxIdleConnsPerHost:     c.settings.MaxIdleConnsPerHost,
+		IdleConnTimeout:         c.settings.IdleConnTimeout,
+		AllowPrivateTrackerEdit: c.settings.AllowPrivateTrackerEdit,
+		RateLimit:               c.settings.RateLimit,
+		RateLimitBurst:          c.settings.RateLimitBurst,
+		MaxConcurrentRPC:        c.settings.MaxConcurrentRPC,
+		MaxRetryAfter:           c.settings.MaxRetryAfter,
+		MaxTorrentSetBatch:      c.settings.MaxTorrentSetBatch,
+		RoundSeedIdleLimit:      c.settings.RoundSeedIdleLimit,
+		ReconnectOnDisconnect:   c.settings.ReconnectOnDisconnect,
+		AddDefaults:             c.settings.AddDefaults,
+		RejectDuplicateTorrents: c.settings.RejectDuplicateTorrents,
+		UnsupportedFields:       c.settings.UnsupportedFields,
+	}
+	if c.http != nil {
+		config.Timeout = c.http.Timeout
+	}
+	return
+}
+
+// NewClientFromConfig returns a client built from an exported configuration (see Client.Config()).
+// credentials (optional) are used for the endpoint basic authentication, for example url.UserPassword(user, password).
+func NewClientFromConfig(config ClientConfig, credentials *url.Userinfo) (c *Client, err error) {
+	if config.Host == "" {
+		err = errors.New("configuration host can not be empty")
+		return
+	}
+	if config.Scheme == "" {
+		config.Scheme = "http"
+	}
+	endpoint := &url.URL{
+		Scheme: config.Scheme,
+		Host:   config.Host,
+		Path:   config.Path,
+		User:   credentials,
+	}
+	if config.Port != "" {
+		endpoint.Host = net.JoinHostPort(config.Host, config.Port)
+	}
+	extra := &Config{
+		UserAgent:               config.UserAgent,
+		KeepLastRawResponse:     config.KeepLastRawResponse,
+		SessionCacheTTL:         config.SessionCacheTTL,
+		MaxIdleConns:            config.MaxIdleConns,
+		MaxIdleConnsPerHost:     config.MaxIdleConnsPerHost,
+		IdleConnTimeout:         config.IdleConnTimeout,
+		AllowPrivateTrackerEdit: config.AllowPrivateTrackerEdit,
+		RateLimit:               config.RateLimit,
+		RateLimitBurst:          config.RateLimitBurst,
+		MaxConcurrentRPC:        config.MaxConcurrentRPC,
+		MaxRetryAfter:           config.MaxRetryAfter,
+		MaxTorrentSetBatch:      config.MaxTorrentSetBatch,
+		RoundSeedIdleLimit:      config.RoundSeedIdleLimit,
+		ReconnectOnDisconnect:   config.ReconnectOnDisconnect,
+		AddDefaults:             config.AddDefaults,
+		RejectDuplicateTorrents: config.RejectDuplicateTorrents,
+		UnsupportedFields:       config.UnsupportedFields,
+	}
+	extra.CustomClient = newPooledClient(extra)
+	extra.CustomClient.Timeout = config.Timeout
+	if c, err = New(endpoint, extra); err != nil {
+		err = fmt.Errorf("can't create client from configuration: %w", err)
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package transmissionrpc
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+	Connection state
+	Tracks if the daemon is reachable from the outcome of the RPC calls
+*/
+
+type connectionState struct {
+	known     bool
+	connected bool
+	access    sync.Mutex
+}
+
+// IsConnected returns true if the last RPC call reached the daemon (even if the call itself failed).
+// It returns false if no call has been made yet.
+func (c *Client) IsConnected() bool {
+	defer c.connection.access.Unlock()
+	c.connection.access.Lock()
+	return c.connection.connected
+}
+
+// updateConnectionState tracks the connection state from the outcome of a call and triggers the callbacks on state
+// changes. Calls interrupted by their context do not change the state.
+func (c *Client) updateConnectionState(ctx context.Context, err error) {
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+	connected := err == nil || !isTransientError(err)
+	c.connection.access.Lock()
+	changed := !c.connection.known || c.connection.connected != connected
+	c.connection.known = true
+	c.connection.connected = connected
+	c.connection.access.Unlock()
+	if !changed {
+		return
+	}
+	if connected {
+		if c.settings.OnConnected != nil {
+			c.settings.OnConnected()
+		}
+		return
+	}
+	if c.settings.ReconnectOnDisconnect {
+		// Drop the (probably dead) kept alive connections and the session id, renegotiated by the next call
+		c.http.CloseIdleConnections()
+		c.updateSessionID("")
+	}
+	if c.settings.OnDisconnected != nil {
+		c.settings.OnDisconnected(err)
+	}
+}
@@ -1,6 +1,7 @@
 package transmissionrpc
 
 import (
+	"encoding/json"
 	"errors"
 	"math/rand"
 	"net/http"
@@ -15,6 +16,8 @@ const (
 	// RPCVersion indicates the exact transmission RPC version this library is build against
 	RPCVersion       = 17
 	defaultUserAgent = "github.com/hekmon/transmissionrpc"
+	// defaultMaxRetryAfter is the default value of Config.MaxRetryAfter
+	defaultMaxRetryAfter = time.Minute
 )
 
 // Config is the input data needed to make a connection to Transmission RPC.
@@ -23,6 +26,71 @@ type Config struct {
 	UserAgent string
 	// Client is set to a clean and isolated client if not provided
 	CustomClient *http.Client
+	// KeepLastRawResponse makes the client retain the raw 'arguments' of the last answer received,
+	// see Client.LastRawResponse(). Useful to debug decoding errors.
+	KeepLastRawResponse bool
+	// SessionCacheTTL enables the caching of session-get answers (see Client.SessionArgumentsGetAll()) for this duration.
+	// Zero (default) disables the cache.
+	SessionCacheTTL time.Duration
+	// Connection pool tuning of the default HTTP client, ignored if CustomClient is provided.
+	// Zero values keep the defaults: 100 idle connections, GOMAXPROCS+1 idle connections per host
+	// and a 90 seconds idle timeout. Polling a single daemon at high frequency usually benefits
+	// from a higher MaxIdleConnsPerHost.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// AllowPrivateTrackerEdit allows TorrentSet to add trackers to private torrents. Adding public trackers
+	// to private torrents is usually forbidden by private trackers: this is refused by default.
+	AllowPrivateTrackerEdit bool
+	// RateLimit caps the sustained rate of RPC calls (requests per second) with a token bucket allowing bursts
+	// of RateLimitBurst calls (minimum 1). Calls wait for a token, or for their context to be done.
+	// Zero (default) disables the limit.
+	RateLimit      float64
+	RateLimitBurst int
+	// MaxConcurrentRPC caps the number of RPC requests in flight at the same time. Calls beyond it wait for a free
+	// slot, or for their context to be done. Zero (default) means unlimited.
+	MaxConcurrentRPC int
+	// MaxRetryAfter is the longest delay honored when the daemon (or a proxy in front of it) answers 503 with
+	// a Retry-After header: the request is then sent again once after the asked delay.
+	// Longer delays are not waited for and the error is returned. Defaults to 1 minute, negative disables the retry.
+	MaxRetryAfter time.Duration
+	// MaxTorrentSetBatch splits the TorrentSet calls with more ids than this into several sequential torrent-set calls
+	// carrying the same mutators, to avoid too large requests (for example with a proxy in front of the daemon).
+	// Zero (default) means unlimited.
+	MaxTorrentSetBatch int
+	// RoundSeedIdleLimit allows TorrentSet to round down to the minute (the RPC unit) a SeedIdleLimit which is not
+	// a whole number of minutes. Such limits are refused by default.
+	RoundSeedIdleLimit bool
+	// OnConnected (optional) is called when a call reaches the daemon for the first time or after a disconnection.
+	// OnDisconnected (optional) is called when a call fails to reach the daemon (network error or server side HTTP error)
+	// while it was connected (or on first call). Both are called synchronously by the call triggering the change:
+	// they must return quickly. See also Client.IsConnected().
+	OnConnected    func()
+	OnDisconnected func(err error)
+	// ReconnectOnDisconnect closes the idle connections and forgets the session id when a disconnection is detected,
+	// for the next call to start from a fresh connection and session.
+	ReconnectOnDisconnect bool
+	// AddDefaults (optional) are merged into every torrent added by the client (TorrentAdd and its wrappers):
+	// default labels are added, default download dir and seed goals are used when the payload does not set them.
+	AddDefaults *AddDefaults
+	// RejectDuplicateTorrents makes TorrentAdd and its wrappers return an error matching ErrDuplicateTorrent (along with
+	// the already present torrent) instead of succeeding when the torrent is already present. TorrentAddIdempotent is not affected.
+	RejectDuplicateTorrents bool
+	// AuditLog (optional) is called after each mutating call (torrent-set, torrent-add, session-set, actions, etc.)
+	// with a structured and sanitized description of it, see AuditEntry. Reads are not reported.
+	AuditLog func(entry AuditEntry)
+	// UnsupportedFields sets what to do with the torrent-get fields and the torrent-set/torrent-add arguments the server
+	// RPC version does not know (group, labels, trackerList, etc.): send them as is (default), strip them or refuse
+	// the call with a FieldUnsupportedError. The server version is fetched once on first need, see ServerCapabilities().
+	UnsupportedFields UnsupportedFieldsPolicy
+	// Retry (optional) enables the automatic retry of the idempotent calls failing because of a transient condition
+	// (connection reset, daemon restarting, session id renewal failure, etc.), see RetryPolicy. Disabled by default.
+	Retry *RetryPolicy
+	// Hooks (optional) are called around each RPC attempt (retries included), for example to export per method
+	// latencies, errors and payload sizes to a metrics or tracing system, see CallHooks.
+	Hooks *CallHooks
+	// Headers (optional) are added to each request sent to the daemon (for example for an authenticating proxy).
+	Headers http.Header
 }
 
 // New returns an initialized and ready to use Controller
@@ -32,12 +100,28 @@ func New(transmissionRPCendpoint *url.URL, extra *Config) (c *Client, err error)
 		err = errors.New("please provide an Transmission RPC endpoint URL")
 		return
 	}
+	var settings Config
 	if extra != nil {
+		settings = *extra
+		settings.CustomClient = nil
+		if extra.AddDefaults != nil {
+			defaults := *extra.AddDefaults
+			defaults.Labels = append([]string(nil), defaults.Labels...)
+			settings.AddDefaults = &defaults
+		}
+		if extra.Hooks != nil {
+			hooks := *extra.Hooks
+			settings.Hooks = &hooks
+		}
+		if extra.Retry != nil {
+			policy := *extra.Retry
+			settings.Retry = &policy
+		}
 		if extra.UserAgent == "" {
 			extra.UserAgent = defaultUserAgent
 		}
 		if extra.CustomClient == nil {
-			extra.CustomClient = cleanhttp.DefaultPooledClient()
+			extra.CustomClient = newPooledClient(extra)
 		}
 	} else {
 		extra = &Config{
@@ -47,41 +131,109 @@ func New(transmissionRPCendpoint *url.URL, extra *Config) (c *Client, err error)
 	}
 	// Initialize & return ready to use client
 	c = &Client{
-		endpoint:     *transmissionRPCendpoint,
-		http:         extra.CustomClient,
-		userAgent:    extra.UserAgent,
-		tagGenerator: rand.New(newLockedRandomSource(time.Now().Unix())),
+		endpoint:                *transmissionRPCendpoint,
+		http:                    extra.CustomClient,
+		userAgent:               extra.UserAgent,
+		tagGenerator:            rand.New(newLockedRandomSource(time.Now().Unix())),
+		keepLastRaw:             extra.KeepLastRawResponse,
+		sessionCache:            sessionCache{ttl: extra.SessionCacheTTL},
+		allowPrivateTrackerEdit: extra.AllowPrivateTrackerEdit,
+		maxTorrentSetBatch:      extra.MaxTorrentSetBatch,
+		roundSeedIdleLimit:      extra.RoundSeedIdleLimit,
+		rateLimiter:             newRateLimiter(extra.RateLimit, extra.RateLimitBurst),
+		maxRetryAfter:           extra.MaxRetryAfter,
+		settings:                settings,
+		headers:                 extra.Headers.Clone(),
+		latencies:               latencyHistogram{since: time.Now()},
+	}
+	if c.maxRetryAfter == 0 {
+		c.maxRetryAfter = defaultMaxRetryAfter
+	}
+	if extra.MaxConcurrentRPC > 0 {
+		c.rpcSlots = make(chan struct{}, extra.MaxConcurrentRPC)
+	}
+	return
+}
+
+// newPooledClient returns a clean pooled HTTP client tuned with the connection pool settings of the config.
+func newPooledClient(extra *Config) (client *http.Client) {
+	client = cleanhttp.DefaultPooledClient()
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return
+	}
+	if extra.MaxIdleConns > 0 {
+		transport.MaxIdleConns = extra.MaxIdleConns
+	}
+	if extra.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = extra.MaxIdleConnsPerHost
+	}
+	if extra.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = extra.IdleConnTimeout
 	}
 	return
 }
 
 // Client is the base object to interract with a remote transmission rpc endpoint.
-// It must be created with New().
+// It must be created with New() and is safe for concurrent use by multiple goroutines.
 type Client struct {
 	// HTTP Client
 	endpoint  url.URL
 	http      *http.Client
 	userAgent string
+	headers   http.Header
 	// Transmission RPC protections
-	tagGenerator    *rand.Rand
-	sessionID       string
-	sessionIDAccess sync.RWMutex
+	tagGenerator *rand.Rand
+	sessionID    sessionID
+	// Throttling
+	rateLimiter   *rateLimiter
+	rpcSlots      chan struct{}
+	maxRetryAfter time.Duration
+	// Behavior
+	allowPrivateTrackerEdit bool
+	maxTorrentSetBatch      int
+	roundSeedIdleLimit      bool
+	settings                Config // as provided to New(), without CustomClient
+	// State
+	connection      connectionState
+	latencies       latencyHistogram
+	verifyRunStates verifyRunStates
+	// Cache
+	sessionCache  sessionCache
+	serverVersion serverVersion
+	// Debug
+	keepLastRaw   bool
+	lastRaw       json.RawMessage
+	lastRawAccess sync.Mutex
 }
 
-func (c *Client) getRandomTag() int {
-	return c.tagGenerator.Int()
+// LastRawResponse returns a copy of the raw 'arguments' of the last answer received, even if its decoding failed.
+// Config.KeepLastRawResponse must be set for the client to retain it, nil is returned otherwise.
+func (c *Client) LastRawResponse() (raw json.RawMessage) {
+	defer c.lastRawAccess.Unlock()
+	c.lastRawAccess.Lock()
+	if c.lastRaw != nil {
+		raw = make(json.RawMessage, len(c.lastRaw))
+		copy(raw, c.lastRaw)
+	}
+	return
 }
 
-func (c *Client) getSessionID() string {
-	defer c.sessionIDAccess.RUnlock()
-	c.sessionIDAccess.RLock()
-	return c.sessionID
+func (c *Client) updateLastRaw(raw json.RawMessage) {
+	defer c.lastRawAccess.Unlock()
+	c.lastRawAccess.Lock()
+	c.lastRaw = raw
+}
+
+func (c *Client) getRandomTag() int {
+	return c.tagGenerator.Int()
 }
 
 func (c *Client) updateSessionID(newID string) {
-	defer c.sessionIDAccess.Unlock()
-	c.sessionIDAccess.Lock()
-	c.sessionID = newID
+	if previous := c.sessionID.update(newID); previous != "" && previous != newID {
+		c.serverVersion.reset()
+		c.verifyRunStates.reset()
+	}
 }
 
 // rand.NewSource is not thread-safe, so access should be serialized
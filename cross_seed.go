@@ -0,0 +1,107 @@
+package transmissionrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+/*
+	Cross seeding
+	Built on torrent-get on 2 daemons
+*/
+
+// crossSeedSizeTolerance is the relative size difference accepted between 2 torrents of the same content
+// (padding files or small extra files as .nfo may differ between releases).
+const crossSeedSizeTolerance = 0.001
+
+// CrossSeedMatch is a pair of torrents which look like the same content under different info hashes.
+type CrossSeedMatch struct {
+	Local     Torrent // torrent of this client daemon
+	Remote    Torrent // torrent of the other client daemon
+	SizeDelta int64   // Remote total size minus Local total size, in bytes
+}
+
+// CrossSeedCandidates compares the torrents of this client and other ones by normalized name (case, separators and
+// punctuation are ignored) and total size (a 0.1% difference is tolerated) and returns the pairs which look like the
+// same content with different info hashes. Torrents with the same info hash on both sides are not reported.
+// Returned torrents have the id, name, hashString, totalSize and downloadDir fields.
+func (c *Client) CrossSeedCandidates(ctx context.Context, other *Client) (matches []CrossSeedMatch, err error) {
+	if other == nil {
+		err = errors.New("other client can not be nil")
+		return
+	}
+	fields := []string{"id", "name", "hashString", "totalSize", "downloadDir"}
+	local, err := c.torrentGet(ctx, fields, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't get local torrents: %w", err)
+	}
+	remote, err := other.torrentGet(ctx, fields, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't get other torrents: %w", err)
+	}
+	// Index the other side by normalized name
+	byName := make(map[string][]Torrent, len(remote))
+	for _, torrent := range remote {
+		if torrent.Name == nil || torrent.TotalSize == nil {
+			continue
+		}
+		name := normalizeCrossSeedName(*torrent.Name)
+		byName[name] = append(byName[name], torrent)
+	}
+	// Match
+	for _, localTorrent := range local {
+		if localTorrent.Name == nil || localTorrent.TotalSize == nil {
+			continue
+		}
+		localSize := int64(localTorrent.TotalSize.Byte())
+		for _, remoteTorrent := range byName[normalizeCrossSeedName(*localTorrent.Name)] {
+			if localTorrent.HashString != nil && remoteTorrent.HashString != nil &&
+				strings.EqualFold(*localTorrent.HashString, *remoteTorrent.HashString) {
+				continue
+			}
+			remoteSize := int64(remoteTorrent.TotalSize.Byte())
+			if !sizesMatch(localSize, remoteSize) {
+				continue
+			}
+			matches = append(matches, CrossSeedMatch{
+				Local:     localTorrent,
+				Remote:    remoteTorrent,
+				SizeDelta: remoteSize - localSize,
+			})
+		}
+	}
+	return
+}
+
+// normalizeCrossSeedName lowercases name and reduces every run of non alphanumeric characters to a single space.
+func normalizeCrossSeedName(name string) string {
+	var builder strings.Builder
+	builder.Grow(len(name))
+	separator := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if separator && builder.Len() > 0 {
+				builder.WriteByte(' ')
+			}
+			separator = false
+			builder.WriteRune(r)
+		} else {
+			separator = true
+		}
+	}
+	return builder.String()
+}
+
+func sizesMatch(a, b int64) bool {
+	if a <= 0 || b <= 0 {
+		return false
+	}
+	delta, largest := a-b, a
+	if delta < 0 {
+		delta, largest = -delta, b
+	}
+	return float64(delta) <= float64(largest)*crossSeedSizeTolerance
+}
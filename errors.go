@@ -0,0 +1,254 @@
+package transmissionrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+/*
+	Errors
+*/
+
+// Failure modes matched (with errors.Is()) by the errors returned by the client methods.
+var (
+	// ErrUnauthorized is matched when the daemon refused the credentials (HTTP 401).
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is matched when the daemon refused the client address or host name (HTTP 403, see the rpc whitelists).
+	ErrForbidden = errors.New("forbidden")
+	// ErrSessionIDRenewal is matched when the session id (CSRF token) negotiation with the daemon failed (HTTP 409).
+	ErrSessionIDRenewal = errors.New("session id renewal failed")
+	// ErrInvalidArgument is matched when the daemon refused the request arguments.
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrDuplicateTorrent is returned by the add methods when Config.RejectDuplicateTorrents is set and the torrent
+	// was already present on the daemon.
+	ErrDuplicateTorrent = errors.New("duplicate torrent")
+)
+
+// OpError is the error of one of the calls sent together by Batch.Commit() or TorrentSetMulti().
+type OpError struct {
+	Index  int     // index of the failed call (payload index for TorrentSetMulti)
+	Method string  // RPC method of the call
+	IDs    []int64 // ids of the call, nil if it was sent with a selector
+	Err    error
+}
+
+func (oe OpError) Error() string {
+	if oe.IDs == nil {
+		return fmt.Sprintf("'%s' call %d failed: %v", oe.Method, oe.Index, oe.Err)
+	}
+	return fmt.Sprintf("'%s' call %d for ids %v failed: %v", oe.Method, oe.Index, oe.IDs, oe.Err)
+}
+
+// Unwrap returns the underlying call error.
+func (oe OpError) Unwrap() error {
+	return oe.Err
+}
+
+// MultiError is returned when at least one of the calls sent together failed. The other calls are not rolled back.
+type MultiError struct {
+	Failed []OpError // ordered by index
+}
+
+func (me MultiError) Error() string {
+	messages := make([]string, len(me.Failed))
+	for index, failed := range me.Failed {
+		messages[index] = failed.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the errors of the failed calls.
+func (me MultiError) Unwrap() []error {
+	errs := make([]error, len(me.Failed))
+	for index, failed := range me.Failed {
+		errs[index] = failed
+	}
+	return errs
+}
+
+// newMultiError returns the MultiError of the non nil errs, nil if there is none. describe returns the method and ids
+// of a call from its index.
+func newMultiError(errs []error, describe func(index int) (method string, ids []int64)) (err error) {
+	var multiErr MultiError
+	for index, opErr := range errs {
+		if opErr == nil {
+			continue
+		}
+		method, ids := describe(index)
+		multiErr.Failed = append(multiErr.Failed, OpError{Index: index, Method: method, IDs: ids, Err: opErr})
+	}
+	if len(multiErr.Failed) > 0 {
+		err = multiErr
+	}
+	return
+}
+
+// invalidArgumentResults are (lower cased) fragments of the daemon answers refusing an argument.
+var invalidArgumentResults = []string{"invalid", "not valid", "absolute path", "unrecognized"}
+
+// RPCError is returned (wrapped) when the daemon answered but did not perform the request: either with a failure
+// HTTP status (HTTPStatus, Err then being the HTTPStatusCode) or with a failure result string (Result, HTTPStatus is 200).
+// Transport errors (network, context) are not RPCError.
+type RPCError struct {
+	Method     string
+	Result     string
+	HTTPStatus int
+	Err        error
+}
+
+func (re RPCError) Error() string {
+	if re.Err != nil {
+		return re.Err.Error()
+	}
+	return fmt.Sprintf("http request ok but payload does not indicate success: %s", re.Result)
+}
+
+// Unwrap returns the underlying error (the HTTPStatusCode for HTTP failures).
+func (re RPCError) Unwrap() error {
+	return re.Err
+}
+
+// Is allows errors.Is() to match ErrUnauthorized, ErrForbidden, ErrSessionIDRenewal and ErrInvalidArgument.
+func (re RPCError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return re.HTTPStatus == http.StatusUnauthorized
+	case ErrForbidden:
+		return re.HTTPStatus == http.StatusForbidden
+	case ErrSessionIDRenewal:
+		return re.HTTPStatus == http.StatusConflict
+	case ErrInvalidArgument:
+		if re.Result == "" {
+			return false
+		}
+		result := strings.ToLower(re.Result)
+		for _, fragment := range invalidArgumentResults {
+			if strings.Contains(result, fragment) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// asRPCError wraps the HTTP failures of method into a RPCError.
+func asRPCError(method string, err error) error {
+	var rpcErr RPCError
+	if err == nil || errors.As(err, &rpcErr) {
+		return err
+	}
+	var statusCode HTTPStatusCode
+	if !errors.As(err, &statusCode) {
+		return err
+	}
+	return RPCError{
+		Method:     method,
+		HTTPStatus: int(statusCode),
+		Err:        err,
+	}
+}
+
+// ErrTorrentNotFound is matched (with errors.Is()) by the TorrentNotFoundError returned by the single torrent methods
+// when the requested torrent is unknown to the daemon.
+var ErrTorrentNotFound = errors.New("torrent not found")
+
+// TorrentNotFoundError is returned when the requested torrent(s) can not be found. It carries the requested ids or hashes.
+type TorrentNotFoundError struct {
+	IDs    []int64
+	Hashes []string
+}
+
+func (tnfe TorrentNotFoundError) Error() string {
+	switch {
+	case len(tnfe.IDs) == 1:
+		return fmt.Sprintf("torrent id %d not found", tnfe.IDs[0])
+	case len(tnfe.IDs) > 1:
+		return fmt.Sprintf("torrent ids %v not found", tnfe.IDs)
+	case len(tnfe.Hashes) == 1:
+		return fmt.Sprintf("torrent hash %s not found", tnfe.Hashes[0])
+	case len(tnfe.Hashes) > 1:
+		return fmt.Sprintf("torrent hashes %v not found", tnfe.Hashes)
+	default:
+		return ErrTorrentNotFound.Error()
+	}
+}
+
+// Is allows errors.Is(err, ErrTorrentNotFound) to match.
+func (tnfe TorrentNotFoundError) Is(target error) bool {
+	return target == ErrTorrentNotFound
+}
+
+// FormatError returns a concise and actionable message for err, suitable for CLI users.
+// Errors not recognized are returned as is (err.Error()). An empty string is returned for a nil error.
+func FormatError(err error) string {
+	if err == nil {
+		return ""
+	}
+	// Sequences: format the step error
+	var seqErr SequenceError
+	if errors.As(err, &seqErr) {
+		return fmt.Sprintf("step %d (%s) failed: %s", seqErr.Step+1, seqErr.Name, FormatError(seqErr.Err))
+	}
+	// Torrents
+	var notFound TorrentNotFoundError
+	if errors.As(err, &notFound) {
+		return notFound.Error() + ": it may have been removed, refresh the torrent list"
+	}
+	// HTTP layer
+	var statusCode HTTPStatusCode
+	if errors.As(err, &statusCode) {
+		switch {
+		case statusCode == http.StatusUnauthorized:
+			return "authentication failed: check username/password"
+		case statusCode == http.StatusForbidden:
+			return "access forbidden: check the daemon rpc-whitelist and rpc-host-whitelist settings"
+		case statusCode == http.StatusConflict:
+			return "session negotiation with the daemon failed: check that no proxy strips the X-Transmission-Session-Id header"
+		case statusCode == http.StatusNotFound:
+			return "RPC endpoint not found: check the URL path (usually /transmission/rpc)"
+		case statusCode >= 500:
+			return fmt.Sprintf("daemon error (HTTP %d): try again later", int(statusCode))
+		default:
+			return fmt.Sprintf("unexpected answer from the daemon (HTTP %d)", int(statusCode))
+		}
+	}
+	// Daemon refusals
+	var rpcErr RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Result != "" {
+		if errors.Is(rpcErr, ErrInvalidArgument) {
+			return fmt.Sprintf("the daemon refused an argument of '%s': %s", rpcErr.Method, rpcErr.Result)
+		}
+		return fmt.Sprintf("the daemon refused '%s': %s", rpcErr.Method, rpcErr.Result)
+	}
+	// Library errors
+	switch {
+	case errors.Is(err, ErrDuplicateTorrent):
+		return "the torrent is already present on the daemon"
+	case errors.Is(err, ErrUnsupportedRPCVersion):
+		return "the daemon is too old for this operation: upgrade Transmission"
+	case errors.Is(err, ErrInvalidLocation):
+		return "invalid location: use a non empty absolute path on the daemon host"
+	case errors.Is(err, ErrPrivateTrackerEdit):
+		return "refused to add trackers to a private torrent: set Config.AllowPrivateTrackerEdit to force it"
+	case errors.Is(err, ErrVerifyQueuedTimeout):
+		return "verification timed out while queued: other torrents are being verified, try a longer timeout"
+	case errors.Is(err, ErrVerifyTimeout):
+		return "verification timed out: try a longer timeout"
+	}
+	// Context & network
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "request timed out: the daemon did not answer in time"
+	case errors.Is(err, context.Canceled):
+		return "operation canceled"
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return fmt.Sprintf("can't reach the daemon: check the URL and that it is running (%v)", netErr)
+	}
+	return err.Error()
+}
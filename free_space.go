@@ -16,7 +16,7 @@ import (
 func (c *Client) FreeSpace(ctx context.Context, path string) (freeSpace, totalSize cunits.Bits, err error) {
 	payload := &transmissionFreeSpacePayload{Path: path}
 	var space TransmissionFreeSpace
-	if err = c.rpcCall(ctx, "free-space", payload, &space); err == nil {
+	if err = c.rpcCall(ctx, MethodFreeSpace, payload, &space); err == nil {
 		if space.Path == path {
 			freeSpace = cunits.ImportInByte(float64(space.Size))
 			totalSize = cunits.ImportInByte(float64(space.TotalSize))
@@ -0,0 +1,83 @@
+package transmissionrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+/*
+	Health check
+	Built on session-stats, port-test, torrent-get, session-get and free-space
+*/
+
+// healthMinFreeSpace is the free space (bytes) under which the download dir is considered unhealthy
+const healthMinFreeSpace = 1000 * 1000 * 1000
+
+// Health is the consolidated result of HealthCheck(). Each check error is kept along with its result.
+type Health struct {
+	// Healthy is true if all the checks succeeded: daemon responsive, peer port open,
+	// no torrent in error and at least 1 GB free in the default download dir.
+	Healthy bool
+	// Daemon responsiveness (session-stats)
+	ResponseTime time.Duration
+	// Peer port (port-test)
+	PortOpen bool
+	PortErr  error
+	// Torrents in error (torrent-get)
+	ErrorTorrents []int64
+	TorrentsErr   error
+	// Default download dir free space (session-get and free-space)
+	DownloadDir  string
+	FreeSpace    int64 // bytes
+	FreeSpaceErr error
+}
+
+// HealthCheck runs all the health checks and consolidates them. err is only set if the daemon is not responsive,
+// the others checks failures are reported within health.
+func (c *Client) HealthCheck(ctx context.Context) (health Health, err error) {
+	// Is the daemon responsive
+	start := time.Now()
+	if _, err = c.SessionStats(ctx); err != nil {
+		err = fmt.Errorf("daemon is not responsive: %w", err)
+		return
+	}
+	health.ResponseTime = time.Since(start)
+	// Peer port
+	health.PortOpen, health.PortErr = c.PortTest(ctx)
+	// Torrents in error
+	var torrents []Torrent
+	if torrents, health.TorrentsErr = c.torrentGet(ctx, []string{"id", "error"}, nil); health.TorrentsErr == nil {
+		for _, torrent := range torrents {
+			if torrent.ID != nil && torrent.Error != nil && *torrent.Error != 0 {
+				health.ErrorTorrents = append(health.ErrorTorrents, *torrent.ID)
+			}
+		}
+	}
+	// Free space
+	health.DownloadDir, health.FreeSpace, health.FreeSpaceErr = c.downloadDirFreeSpace(ctx)
+	// Consolidate
+	health.Healthy = health.PortErr == nil && health.PortOpen &&
+		health.TorrentsErr == nil && len(health.ErrorTorrents) == 0 &&
+		health.FreeSpaceErr == nil && health.FreeSpace >= healthMinFreeSpace
+	return
+}
+
+func (c *Client) downloadDirFreeSpace(ctx context.Context) (downloadDir string, freeSpace int64, err error) {
+	session, err := c.SessionArgumentsGet(ctx, []string{"download-dir"})
+	if err != nil {
+		return
+	}
+	if session.DownloadDir == nil {
+		err = errors.New("download dir is missing from the session-get answer")
+		return
+	}
+	downloadDir = *session.DownloadDir
+	free, _, err := c.FreeSpace(ctx, downloadDir)
+	if err != nil {
+		return
+	}
+	freeSpace = int64(free.Byte())
+	return
+}
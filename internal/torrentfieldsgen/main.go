@@ -0,0 +1,92 @@
+// Command torrentfieldsgen generates the TorrentField constants from the json tags of the Torrent struct fields.
+// It is run by go generate from the root package directory.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strconv"
+)
+
+const (
+	source = "torrent_accessors.go"
+	output = "torrent_fields_gen.go"
+)
+
+type field struct {
+	goName string
+	rpc    string
+}
+
+func main() {
+	fields, err := torrentFields()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by torrentfieldsgen from the Torrent struct; DO NOT EDIT.\n\n")
+	buf.WriteString("package transmissionrpc\n\n")
+	buf.WriteString("// Torrent fields, as accepted by torrent-get\n")
+	buf.WriteString("const (\n")
+	for _, f := range fields {
+		fmt.Fprintf(&buf, "\tTorrentField%s TorrentField = %s\n", f.goName, strconv.Quote(f.rpc))
+	}
+	buf.WriteString(")\n\n")
+	buf.WriteString("// FieldsAll holds all the torrent fields known by the library, some of them requiring a recent RPC version.\n")
+	buf.WriteString("var FieldsAll = TorrentFields{\n")
+	for _, f := range fields {
+		fmt.Fprintf(&buf, "\tTorrentField%s,\n", f.goName)
+	}
+	buf.WriteString("}\n")
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err = os.WriteFile(output, formatted, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// torrentFields returns the Go name and the json tag of each Torrent struct field.
+func torrentFields() (fields []field, err error) {
+	file, err := parser.ParseFile(token.NewFileSet(), source, nil, 0)
+	if err != nil {
+		return
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != "Torrent" {
+			return true
+		}
+		structType, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+		for _, structField := range structType.Fields.List {
+			if structField.Tag == nil || len(structField.Names) == 0 {
+				continue
+			}
+			tag, unquoteErr := strconv.Unquote(structField.Tag.Value)
+			if unquoteErr != nil {
+				continue
+			}
+			if rpc := reflect.StructTag(tag).Get("json"); rpc != "" && rpc != "-" {
+				fields = append(fields, field{goName: structField.Names[0].Name, rpc: rpc})
+			}
+		}
+		return false
+	})
+	if len(fields) == 0 {
+		err = fmt.Errorf("no Torrent struct fields found in %s", source)
+	}
+	return
+}
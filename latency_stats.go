@@ -0,0 +1,98 @@
+package transmissionrpc
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+	Latency statistics
+	Per method histogram of the RPC calls durations, see Client.Latencies()
+*/
+
+const (
+	latencyFirstBucket = 100 * time.Microsecond // upper bound of the first bucket, each next one doubles it
+	latencyBuckets     = 22                     // last bucket catches everything above ~1.7 minutes
+)
+
+// MethodLatency holds the latency percentiles of an RPC method. Percentiles are the upper bound of the histogram bucket
+// they fall in: they are accurate within a factor of 2.
+type MethodLatency struct {
+	Count uint64
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+}
+
+// LatencyStats holds the latencies of the RPC calls (including their retries and waits) per method, see Client.Latencies().
+type LatencyStats struct {
+	Since   time.Time // client creation or last reset
+	Methods map[string]MethodLatency
+}
+
+type latencyHistogram struct {
+	since   time.Time
+	methods map[string]*[latencyBuckets]uint64
+	access  sync.Mutex
+}
+
+func (lh *latencyHistogram) record(method string, elapsed time.Duration) {
+	bucket := 0
+	for bound := latencyFirstBucket; elapsed > bound && bucket < latencyBuckets-1; bound *= 2 {
+		bucket++
+	}
+	defer lh.access.Unlock()
+	lh.access.Lock()
+	if lh.methods == nil {
+		lh.methods = make(map[string]*[latencyBuckets]uint64)
+	}
+	counts := lh.methods[method]
+	if counts == nil {
+		counts = new([latencyBuckets]uint64)
+		lh.methods[method] = counts
+	}
+	counts[bucket]++
+}
+
+// Latencies returns the latencies of the RPC calls made since the client creation (or the last ResetLatencies()).
+func (c *Client) Latencies() (stats LatencyStats) {
+	defer c.latencies.access.Unlock()
+	c.latencies.access.Lock()
+	stats.Since = c.latencies.since
+	stats.Methods = make(map[string]MethodLatency, len(c.latencies.methods))
+	for method, counts := range c.latencies.methods {
+		var latency MethodLatency
+		for _, count := range counts {
+			latency.Count += count
+		}
+		latency.P50 = latencyPercentile(counts, latency.Count, 0.50)
+		latency.P90 = latencyPercentile(counts, latency.Count, 0.90)
+		latency.P99 = latencyPercentile(counts, latency.Count, 0.99)
+		stats.Methods[method] = latency
+	}
+	return
+}
+
+// ResetLatencies drops the latencies recorded so far.
+func (c *Client) ResetLatencies() {
+	defer c.latencies.access.Unlock()
+	c.latencies.access.Lock()
+	c.latencies.since = time.Now()
+	c.latencies.methods = nil
+}
+
+func latencyPercentile(counts *[latencyBuckets]uint64, total uint64, percentile float64) time.Duration {
+	rank := uint64(percentile*float64(total) + 0.5)
+	if rank == 0 {
+		rank = 1
+	}
+	var cumulated uint64
+	bound := latencyFirstBucket
+	for bucket, count := range counts {
+		if cumulated += count; cumulated >= rank || bucket == latencyBuckets-1 {
+			break
+		}
+		bound *= 2
+	}
+	return bound
+}
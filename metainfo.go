@@ -0,0 +1,199 @@
+package transmissionrpc
+
+import (
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+/*
+	Metainfo helpers
+	Minimal bencode support to compute a torrent info hash locally, before any RPC round trip.
+	https://www.bittorrent.org/beps/bep_0003.html
+*/
+
+const magnetBTIHPrefix = "urn:btih:"
+
+// maxBencodeDepth is the deepest nesting of lists and dictionaries accepted, far beyond what a .torrent file uses,
+// to bound the recursion on hostile content.
+const maxBencodeDepth = 64
+
+// InfoHashFromMetaInfo computes the (v1) info hash of a base64 encoded .torrent content,
+// as expected by the MetaInfo field of TorrentAddPayload. The returned hash is lower case hex encoded,
+// which is the same form as the HashString field of Torrent.
+func InfoHashFromMetaInfo(b64 string) (hash string, err error) {
+	raw, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		err = fmt.Errorf("can't decode base64 metainfo: %w", err)
+		return
+	}
+	return InfoHashFromTorrent(raw)
+}
+
+// InfoHashFromTorrent computes the (v1) info hash of a raw .torrent content.
+// The returned hash is lower case hex encoded, which is the same form as the HashString field of Torrent.
+func InfoHashFromTorrent(raw []byte) (hash string, err error) {
+	start, end, err := bencodeDictValue(raw, "info")
+	if err != nil {
+		err = fmt.Errorf("can't find info dictionary: %w", err)
+		return
+	}
+	sum := sha1.Sum(raw[start:end])
+	hash = hex.EncodeToString(sum[:])
+	return
+}
+
+// InfoHashFromMagnet extracts the (v1) info hash of a magnet link. Both hex and base32
+// encoded btih are supported. The returned hash is lower case hex encoded, which is the
+// same form as the HashString field of Torrent.
+func InfoHashFromMagnet(magnet string) (hash string, err error) {
+	parsed, err := url.Parse(magnet)
+	if err != nil {
+		err = fmt.Errorf("can't parse magnet link: %w", err)
+		return
+	}
+	if parsed.Scheme != "magnet" {
+		err = fmt.Errorf("'%s' is not a magnet scheme", parsed.Scheme)
+		return
+	}
+	for _, xt := range parsed.Query()["xt"] {
+		if !strings.HasPrefix(strings.ToLower(xt), magnetBTIHPrefix) {
+			continue
+		}
+		btih := xt[len(magnetBTIHPrefix):]
+		switch len(btih) {
+		case 40:
+			var decoded []byte
+			if decoded, err = hex.DecodeString(btih); err != nil {
+				err = fmt.Errorf("can't decode hex btih '%s': %w", btih, err)
+				return
+			}
+			hash = hex.EncodeToString(decoded)
+		case 32:
+			var decoded []byte
+			if decoded, err = base32.StdEncoding.DecodeString(strings.ToUpper(btih)); err != nil {
+				err = fmt.Errorf("can't decode base32 btih '%s': %w", btih, err)
+				return
+			}
+			hash = hex.EncodeToString(decoded)
+		default:
+			err = fmt.Errorf("btih '%s' has an unexpected length of %d", btih, len(btih))
+		}
+		return
+	}
+	err = errors.New("magnet link does not contain a btih exact topic")
+	return
+}
+
+// InfoHash returns the info hash of the torrent the payload is about to add, if it can be known locally:
+// either by parsing the MetaInfo content or the magnet link set as Filename. Torrents added by
+// filename or URL can not be hashed before being fetched by the daemon and will yield an error.
+func (tap TorrentAddPayload) InfoHash() (hash string, err error) {
+	switch {
+	case tap.MetaInfo != nil:
+		return InfoHashFromMetaInfo(*tap.MetaInfo)
+	case tap.Filename != nil && strings.HasPrefix(*tap.Filename, "magnet:"):
+		return InfoHashFromMagnet(*tap.Filename)
+	default:
+		err = errors.New("info hash can only be computed from a metainfo content or a magnet link")
+		return
+	}
+}
+
+// bencodeDictValue returns the boundaries of the raw value associated to key within the top level bencoded dictionary.
+func bencodeDictValue(data []byte, key string) (start, end int, err error) {
+	if len(data) == 0 || data[0] != 'd' {
+		err = errors.New("data is not a bencoded dictionary")
+		return
+	}
+	var (
+		currentKey  string
+		pos, keyEnd int
+	)
+	pos = 1
+	for pos < len(data) && data[pos] != 'e' {
+		if currentKey, keyEnd, err = bencodeString(data, pos); err != nil {
+			return
+		}
+		if end, err = bencodeSkip(data, keyEnd, 1); err != nil {
+			return
+		}
+		if currentKey == key {
+			start = keyEnd
+			return
+		}
+		pos = end
+	}
+	err = fmt.Errorf("key '%s' not found", key)
+	return
+}
+
+// bencodeString decodes the bencoded byte string starting at pos and returns it with the position following it.
+func bencodeString(data []byte, pos int) (value string, next int, err error) {
+	colon := pos
+	for colon < len(data) && data[colon] != ':' {
+		colon++
+	}
+	if colon >= len(data) {
+		err = fmt.Errorf("unterminated string length at offset %d", pos)
+		return
+	}
+	length, err := strconv.Atoi(string(data[pos:colon]))
+	if err != nil || length < 0 {
+		err = fmt.Errorf("invalid string length at offset %d", pos)
+		return
+	}
+	if length > len(data)-colon-1 {
+		err = fmt.Errorf("string at offset %d overflows data", pos)
+		return
+	}
+	next = colon + 1 + length
+	value = string(data[colon+1 : next])
+	return
+}
+
+// bencodeSkip returns the position following the bencoded value starting at pos, depth being the number of containers
+// holding it.
+func bencodeSkip(data []byte, pos, depth int) (next int, err error) {
+	if pos >= len(data) {
+		err = errors.New("unexpected end of data")
+		return
+	}
+	switch data[pos] {
+	case 'i':
+		next = pos + 1
+		for next < len(data) && data[next] != 'e' {
+			next++
+		}
+		if next >= len(data) {
+			err = fmt.Errorf("unterminated integer at offset %d", pos)
+			return
+		}
+		next++
+	case 'l', 'd':
+		if depth >= maxBencodeDepth {
+			err = fmt.Errorf("containers nested deeper than %d levels at offset %d", maxBencodeDepth, pos)
+			return
+		}
+		next = pos + 1
+		for next < len(data) && data[next] != 'e' {
+			if next, err = bencodeSkip(data, next, depth+1); err != nil {
+				return
+			}
+		}
+		if next >= len(data) {
+			err = fmt.Errorf("unterminated container at offset %d", pos)
+			return
+		}
+		next++
+	default:
+		_, next, err = bencodeString(data, pos)
+	}
+	return
+}
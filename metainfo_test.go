@@ -0,0 +1,17 @@
+package transmissionrpc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInfoHashFromTorrentNesting(t *testing.T) {
+	info := "4:infod4:name4:testee"
+	if _, err := InfoHashFromTorrent([]byte("d1:a" + strings.Repeat("l", 10) + strings.Repeat("e", 10) + info)); err != nil {
+		t.Errorf("nested lists refused: %v", err)
+	}
+	deep := "d1:a" + strings.Repeat("l", 1<<20) + strings.Repeat("e", 1<<20) + info
+	if _, err := InfoHashFromTorrent([]byte(deep)); err == nil {
+		t.Error("lists nested 1M levels deep accepted")
+	}
+}
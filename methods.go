@@ -0,0 +1,47 @@
+package transmissionrpc
+
+import (
+	"context"
+	"fmt"
+)
+
+// RPC methods names
+// https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md
+const (
+	// Torrent requests
+	MethodTorrentStart       = "torrent-start"
+	MethodTorrentStartNow    = "torrent-start-now"
+	MethodTorrentStop        = "torrent-stop"
+	MethodTorrentVerify      = "torrent-verify"
+	MethodTorrentReannounce  = "torrent-reannounce"
+	MethodTorrentSet         = "torrent-set"
+	MethodTorrentGet         = "torrent-get"
+	MethodTorrentAdd         = "torrent-add"
+	MethodTorrentRemove      = "torrent-remove"
+	MethodTorrentSetLocation = "torrent-set-location"
+	MethodTorrentRenamePath  = "torrent-rename-path"
+	// Session requests
+	MethodSessionSet      = "session-set"
+	MethodSessionGet      = "session-get"
+	MethodSessionStats    = "session-stats"
+	MethodBlocklistUpdate = "blocklist-update"
+	MethodPortTest        = "port-test"
+	MethodSessionClose    = "session-close"
+	MethodQueueMoveTop    = "queue-move-top"
+	MethodQueueMoveUp     = "queue-move-up"
+	MethodQueueMoveDown   = "queue-move-down"
+	MethodQueueMoveBottom = "queue-move-bottom"
+	MethodFreeSpace       = "free-space"
+	MethodGroupSet        = "group-set"
+	MethodGroupGet        = "group-get"
+)
+
+// RawCall is an escape hatch allowing to call any RPC method (see the Method constants) with custom arguments.
+// arguments will be marshalled as the request 'arguments' and the answer 'arguments' will be unmarshalled into result
+// (if not nil). The session id handling and the answer validation are the same as the other methods.
+func (c *Client) RawCall(ctx context.Context, method string, arguments interface{}, result interface{}) (err error) {
+	if err = c.rpcCall(ctx, method, arguments, result); err != nil {
+		err = fmt.Errorf("'%s' rpc method failed: %w", method, err)
+	}
+	return
+}
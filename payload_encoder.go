@@ -0,0 +1,238 @@
+package transmissionrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+/*
+	Payload encoder
+	Reflection free JSON encoding of the "only non nil fields" payloads
+*/
+
+// payloadEncoder appends the fields of a payload as a JSON object. Each method skips nil values: the payloads
+// MarshalJSON() only have to call them in order. The first error is kept and returned by bytes().
+type payloadEncoder struct {
+	buf    []byte
+	fields int
+	err    error
+}
+
+func newPayloadEncoder(capacity int) *payloadEncoder {
+	pe := &payloadEncoder{buf: make([]byte, 1, capacity)}
+	pe.buf[0] = '{'
+	return pe
+}
+
+func (pe *payloadEncoder) bytes() (data []byte, err error) {
+	if pe.err != nil {
+		return nil, pe.err
+	}
+	return append(pe.buf, '}'), nil
+}
+
+func (pe *payloadEncoder) key(name string) {
+	if pe.fields > 0 {
+		pe.buf = append(pe.buf, ',')
+	}
+	pe.fields++
+	pe.buf = appendJSONString(pe.buf, name)
+	pe.buf = append(pe.buf, ':')
+}
+
+func (pe *payloadEncoder) int64(name string, value *int64) {
+	if value == nil {
+		return
+	}
+	pe.key(name)
+	pe.buf = strconv.AppendInt(pe.buf, *value, 10)
+}
+
+func (pe *payloadEncoder) bool(name string, value *bool) {
+	if value == nil {
+		return
+	}
+	pe.key(name)
+	pe.buf = strconv.AppendBool(pe.buf, *value)
+}
+
+func (pe *payloadEncoder) float64(name string, value *float64) {
+	if value == nil {
+		return
+	}
+	if math.IsNaN(*value) || math.IsInf(*value, 0) {
+		if pe.err == nil {
+			pe.err = fmt.Errorf("unsupported value for '%s': %v", name, *value)
+		}
+		return
+	}
+	pe.key(name)
+	pe.buf = appendJSONFloat(pe.buf, *value)
+}
+
+func (pe *payloadEncoder) string(name string, value *string) {
+	if value == nil {
+		return
+	}
+	pe.key(name)
+	pe.buf = appendJSONString(pe.buf, *value)
+}
+
+func (pe *payloadEncoder) int64s(name string, values []int64) {
+	if values == nil {
+		return
+	}
+	pe.key(name)
+	pe.buf = append(pe.buf, '[')
+	for index, value := range values {
+		if index > 0 {
+			pe.buf = append(pe.buf, ',')
+		}
+		pe.buf = strconv.AppendInt(pe.buf, value, 10)
+	}
+	pe.buf = append(pe.buf, ']')
+}
+
+func (pe *payloadEncoder) strings(name string, values []string) {
+	if values == nil {
+		return
+	}
+	pe.key(name)
+	pe.buf = append(pe.buf, '[')
+	for index, value := range values {
+		if index > 0 {
+			pe.buf = append(pe.buf, ',')
+		}
+		pe.buf = appendJSONString(pe.buf, value)
+	}
+	pe.buf = append(pe.buf, ']')
+}
+
+// joinedStrings encodes values as a single string, one value per line (as the tracker lists).
+func (pe *payloadEncoder) joinedStrings(name string, values []string) {
+	if values == nil {
+		return
+	}
+	pe.key(name)
+	pe.buf = append(pe.buf, '"')
+	for index, value := range values {
+		if index > 0 {
+			pe.buf = append(pe.buf, '\\', 'n')
+		}
+		pe.buf = appendJSONStringContent(pe.buf, value)
+	}
+	pe.buf = append(pe.buf, '"')
+}
+
+func (pe *payloadEncoder) selector(name string, value *TorrentSelector) {
+	if value == nil || value.all {
+		return
+	}
+	pe.key(name)
+	if value.recentlyActive {
+		pe.buf = appendJSONString(pe.buf, recentlyActiveSelector)
+		return
+	}
+	pe.buf = append(pe.buf, '[')
+	for index, id := range value.ids {
+		if index > 0 {
+			pe.buf = append(pe.buf, ',')
+		}
+		pe.buf = strconv.AppendInt(pe.buf, id, 10)
+	}
+	for index, hash := range value.hashes {
+		if index > 0 || len(value.ids) > 0 {
+			pe.buf = append(pe.buf, ',')
+		}
+		pe.buf = appendJSONString(pe.buf, hash)
+	}
+	pe.buf = append(pe.buf, ']')
+}
+
+// value encodes the less common types with encoding/json.
+func (pe *payloadEncoder) value(name string, value interface{}) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		if pe.err == nil {
+			pe.err = fmt.Errorf("can't marshal '%s': %w", name, err)
+		}
+		return
+	}
+	pe.key(name)
+	pe.buf = append(pe.buf, encoded...)
+}
+
+// appendJSONString appends the quoted value, escaped as encoding/json does.
+func appendJSONString(buf []byte, value string) []byte {
+	buf = append(buf, '"')
+	buf = appendJSONStringContent(buf, value)
+	return append(buf, '"')
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONStringContent appends the escaped value, without the quotes.
+func appendJSONStringContent(buf []byte, value string) []byte {
+	start := 0
+	for index := 0; index < len(value); {
+		if char := value[index]; char < utf8.RuneSelf {
+			if char >= 0x20 && char != '"' && char != '\\' && char != '<' && char != '>' && char != '&' {
+				index++
+				continue
+			}
+			buf = append(buf, value[start:index]...)
+			switch char {
+			case '"', '\\':
+				buf = append(buf, '\\', char)
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[char>>4], hexDigits[char&0xF])
+			}
+			index++
+			start = index
+			continue
+		}
+		char, size := utf8.DecodeRuneInString(value[index:])
+		if char == utf8.RuneError && size == 1 {
+			buf = append(buf, value[start:index]...)
+			buf = append(buf, "\ufffd"...)
+			index += size
+			start = index
+			continue
+		}
+		if char == '\u2028' || char == '\u2029' {
+			buf = append(buf, value[start:index]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', hexDigits[char&0xF])
+			index += size
+			start = index
+			continue
+		}
+		index += size
+	}
+	return append(buf, value[start:]...)
+}
+
+// appendJSONFloat appends a finite value formatted as encoding/json does.
+func appendJSONFloat(buf []byte, value float64) []byte {
+	format := byte('f')
+	if abs := math.Abs(value); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	buf = strconv.AppendFloat(buf, value, format, -1, 64)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		if n := len(buf); n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
+	}
+	return buf
+}
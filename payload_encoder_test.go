@@ -0,0 +1,193 @@
+package transmissionrpc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// jsonTorrentSetPayload is the encoding/json equivalent of TorrentSetPayload.MarshalJSON(): nil values are omitted,
+// non nil slices (even empty) are kept. Fields are in the encoder order.
+type jsonTorrentSetPayload struct {
+	BandwidthPriority   *int64    `json:"bandwidthPriority,omitempty"`
+	DownloadLimit       *int64    `json:"downloadLimit,omitempty"`
+	DownloadLimited     *bool     `json:"downloadLimited,omitempty"`
+	FilesWanted         *[]int64  `json:"files-wanted,omitempty"`
+	FilesUnwanted       *[]int64  `json:"files-unwanted,omitempty"`
+	Group               *string   `json:"group,omitempty"`
+	HonorsSessionLimits *bool     `json:"honorsSessionLimits,omitempty"`
+	IDs                 *[]int64  `json:"ids,omitempty"`
+	Labels              *[]string `json:"labels,omitempty"`
+	Location            *string   `json:"location,omitempty"`
+	PeerLimit           *int64    `json:"peer-limit,omitempty"`
+	PriorityHigh        *[]int64  `json:"priority-high,omitempty"`
+	PriorityLow         *[]int64  `json:"priority-low,omitempty"`
+	PriorityNormal      *[]int64  `json:"priority-normal,omitempty"`
+	QueuePosition       *int64    `json:"queuePosition,omitempty"`
+	SeedIdleLimit       *int64    `json:"seedIdleLimit,omitempty"`
+	SeedIdleMode        *int64    `json:"seedIdleMode,omitempty"`
+	SeedRatioLimit      *float64  `json:"seedRatioLimit,omitempty"`
+	SeedRatioMode       *int64    `json:"seedRatioMode,omitempty"`
+	SequentialDownload  *bool     `json:"sequential_download,omitempty"`
+	TrackerList         *string   `json:"trackerList,omitempty"`
+	UploadLimit         *int64    `json:"uploadLimit,omitempty"`
+	UploadLimited       *bool     `json:"uploadLimited,omitempty"`
+}
+
+func optionalSlice[T any](values []T) *[]T {
+	if values == nil {
+		return nil
+	}
+	return &values
+}
+
+func newJSONTorrentSetPayload(tsp TorrentSetPayload) (reference jsonTorrentSetPayload) {
+	reference = jsonTorrentSetPayload{
+		BandwidthPriority:   tsp.BandwidthPriority,
+		DownloadLimit:       tsp.DownloadLimit,
+		DownloadLimited:     tsp.DownloadLimited,
+		FilesWanted:         optionalSlice(tsp.FilesWanted),
+		FilesUnwanted:       optionalSlice(tsp.FilesUnwanted),
+		Group:               tsp.Group,
+		HonorsSessionLimits: tsp.HonorsSessionLimits,
+		IDs:                 optionalSlice(tsp.IDs),
+		Labels:              optionalSlice(tsp.Labels),
+		Location:            tsp.Location,
+		PeerLimit:           tsp.PeerLimit,
+		PriorityHigh:        optionalSlice(tsp.PriorityHigh),
+		PriorityLow:         optionalSlice(tsp.PriorityLow),
+		PriorityNormal:      optionalSlice(tsp.PriorityNormal),
+		QueuePosition:       tsp.QueuePosition,
+		SeedIdleMode:        tsp.SeedIdleMode,
+		SeedRatioLimit:      tsp.SeedRatioLimit,
+		SequentialDownload:  tsp.SequentialDownload,
+		UploadLimit:         tsp.UploadLimit,
+		UploadLimited:       tsp.UploadLimited,
+	}
+	if tsp.SeedIdleLimit != nil {
+		sil := int64(*tsp.SeedIdleLimit / time.Minute)
+		reference.SeedIdleLimit = &sil
+	}
+	if tsp.SeedRatioMode != nil {
+		mode := int64(*tsp.SeedRatioMode)
+		reference.SeedRatioMode = &mode
+	}
+	if tsp.TrackerList != nil {
+		list := strings.Join(tsp.TrackerList, "\n")
+		reference.TrackerList = &list
+	}
+	return
+}
+
+func fullTorrentSetPayload() TorrentSetPayload {
+	var (
+		zero     int64
+		limit    int64 = 1024
+		yes            = true
+		no             = false
+		group          = "slow <lane> & \"quotes\""
+		location       = "/downloads/été\n"
+		ratio          = 1.5e-7
+		idle           = 90 * time.Minute
+		mode           = SeedRatioModeCustom
+	)
+	return TorrentSetPayload{
+		BandwidthPriority:   &zero,
+		DownloadLimit:       &limit,
+		DownloadLimited:     &no,
+		FilesWanted:         []int64{0, 1, 2},
+		FilesUnwanted:       []int64{},
+		Group:               &group,
+		HonorsSessionLimits: &yes,
+		IDs:                 []int64{1, 2, 3},
+		Labels:              []string{"movies", "hd", ""},
+		Location:            &location,
+		PeerLimit:           &limit,
+		PriorityHigh:        []int64{4},
+		QueuePosition:       &zero,
+		SeedIdleLimit:       &idle,
+		SeedIdleMode:        &zero,
+		SeedRatioLimit:      &ratio,
+		SeedRatioMode:       &mode,
+		SequentialDownload:  &yes,
+		TrackerList:         []string{"https://tracker.example/announce?a=1&b=2", "", "udp://backup.example:80"},
+		UploadLimit:         &zero,
+		UploadLimited:       &yes,
+	}
+}
+
+func TestTorrentSetPayloadEncoderMatchesEncodingJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload TorrentSetPayload
+	}{
+		{"empty", TorrentSetPayload{}},
+		{"nil slices omitted", TorrentSetPayload{IDs: []int64{1}}},
+		{"empty slices kept", TorrentSetPayload{IDs: []int64{}, Labels: []string{}, TrackerList: []string{}, PriorityLow: []int64{}}},
+		{"zero values kept", TorrentSetPayload{IDs: []int64{1}, DownloadLimit: new(int64), DownloadLimited: new(bool)}},
+		{"all fields", fullTorrentSetPayload()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := test.payload.MarshalJSON()
+			if err != nil {
+				t.Fatalf("MarshalJSON() failed: %v", err)
+			}
+			reference, err := json.Marshal(newJSONTorrentSetPayload(test.payload))
+			if err != nil {
+				t.Fatalf("json.Marshal() failed: %v", err)
+			}
+			if string(encoded) != string(reference) {
+				t.Errorf("encoder output differs from encoding/json\n got: %s\nwant: %s", encoded, reference)
+			}
+		})
+	}
+}
+
+func TestPayloadEncoderFloats(t *testing.T) {
+	for _, value := range []float64{0, 1, -2.5, 1e-7, 123456789, 1e21, 1.7976931348623157e308, 5e-324} {
+		value := value
+		pe := newPayloadEncoder(16)
+		pe.float64("v", &value)
+		encoded, err := pe.bytes()
+		if err != nil {
+			t.Fatalf("encoding %v failed: %v", value, err)
+		}
+		reference, _ := json.Marshal(map[string]float64{"v": value})
+		if string(encoded) != string(reference) {
+			t.Errorf("encoding %v: got %s, want %s", value, encoded, reference)
+		}
+	}
+}
+
+func TestAppendJSONString(t *testing.T) {
+	for _, value := range []string{"", "plain", "quote\" backslash\\", "\n\r\t\x00\x1f", "<a href=\"x\">&</a>", "été 日本",
+		"invalid \xff\xfe utf-8", "line\u2028paragraph\u2029"} {
+		encoded := appendJSONString(nil, value)
+		reference, _ := json.Marshal(value)
+		if string(encoded) != string(reference) {
+			t.Errorf("encoding %q: got %s, want %s", value, encoded, reference)
+		}
+	}
+}
+
+func BenchmarkTorrentSetPayloadEncode(b *testing.B) {
+	payload := fullTorrentSetPayload()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := payload.MarshalJSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTorrentSetPayloadEncodingJSON(b *testing.B) {
+	payload := fullTorrentSetPayload()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(newJSONTorrentSetPayload(payload)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -14,7 +14,7 @@ import (
 func (c *Client) PortTest(ctx context.Context) (open bool, err error) {
 	var result portTestAnswer
 	// Send request
-	if err = c.rpcCall(ctx, "port-test", nil, &result); err == nil {
+	if err = c.rpcCall(ctx, MethodPortTest, nil, &result); err == nil {
 		open = result.PortOpen
 	} else {
 		err = fmt.Errorf("'port-test' rpc method failed: %w", err)
@@ -22,6 +22,42 @@ func (c *Client) PortTest(ctx context.Context) (open bool, err error) {
 	return
 }
 
+// Port test IP protocols (RPC v18)
+const (
+	PortTestIPv4 = "ipv4"
+	PortTestIPv6 = "ipv6"
+)
+
+// PortTestProtocol is PortTest() restricted to one IP protocol (see the PortTest IP protocol constants), as introduced
+// by Transmission 4.1 (RPC v18). An empty protocol lets the daemon choose, as PortTest() does. An error matching
+// ErrUnsupportedRPCVersion is returned by older servers: use PortTest() instead.
+func (c *Client) PortTestProtocol(ctx context.Context, protocol string) (open bool, err error) {
+	if protocol != "" && protocol != PortTestIPv4 && protocol != PortTestIPv6 {
+		return false, fmt.Errorf("invalid port test IP protocol '%s'", protocol)
+	}
+	if err = c.requireRPCVersion(ctx, MethodPortTest, 18); err != nil {
+		err = fmt.Errorf("'port-test' rpc method failed: %w", err)
+		return
+	}
+	var result portTestAnswer
+	// Send request
+	if err = c.rpcCall(ctx, MethodPortTest, &portTestPayload{IPProtocol: protocol}, &result); err != nil {
+		err = fmt.Errorf("'port-test' rpc method failed: %w", err)
+		return
+	}
+	if protocol != "" && result.IPProtocol != "" && result.IPProtocol != protocol {
+		err = fmt.Errorf("returned IP protocol '%s' does not match with requested IP protocol '%s'", result.IPProtocol, protocol)
+		return
+	}
+	open = result.PortOpen
+	return
+}
+
+type portTestPayload struct {
+	IPProtocol string `json:"ipProtocol,omitempty"`
+}
+
 type portTestAnswer struct {
-	PortOpen bool `json:"port-is-open"`
+	PortOpen   bool   `json:"port-is-open"`
+	IPProtocol string `json:"ipProtocol"` // RPC v18
 }
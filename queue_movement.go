@@ -3,6 +3,8 @@ package transmissionrpc
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 )
 
 /*
@@ -13,7 +15,7 @@ import (
 // QueueMoveTop moves IDs to the top of the queue list.
 func (c *Client) QueueMoveTop(ctx context.Context, IDs []int64) (err error) {
 	payload := &queueMovePayload{IDs: IDs}
-	if err = c.rpcCall(ctx, "queue-move-top", payload, nil); err != nil {
+	if err = c.rpcCall(ctx, MethodQueueMoveTop, payload, nil); err != nil {
 		err = fmt.Errorf("'queue-move-top' rpc method failed: %w", err)
 	}
 	return
@@ -22,7 +24,7 @@ func (c *Client) QueueMoveTop(ctx context.Context, IDs []int64) (err error) {
 // QueueMoveUp moves IDs of one position up on the queue list.
 func (c *Client) QueueMoveUp(ctx context.Context, IDs []int64) (err error) {
 	payload := &queueMovePayload{IDs: IDs}
-	if err = c.rpcCall(ctx, "queue-move-up", payload, nil); err != nil {
+	if err = c.rpcCall(ctx, MethodQueueMoveUp, payload, nil); err != nil {
 		err = fmt.Errorf("'queue-move-up' rpc method failed: %w", err)
 	}
 	return
@@ -31,7 +33,7 @@ func (c *Client) QueueMoveUp(ctx context.Context, IDs []int64) (err error) {
 // QueueMoveDown moves IDs of one position down on the queue list.
 func (c *Client) QueueMoveDown(ctx context.Context, IDs []int64) (err error) {
 	payload := &queueMovePayload{IDs: IDs}
-	if err = c.rpcCall(ctx, "queue-move-down", payload, nil); err != nil {
+	if err = c.rpcCall(ctx, MethodQueueMoveDown, payload, nil); err != nil {
 		err = fmt.Errorf("'queue-move-down' rpc method failed: %w", err)
 	}
 	return
@@ -40,12 +42,130 @@ func (c *Client) QueueMoveDown(ctx context.Context, IDs []int64) (err error) {
 // QueueMoveBottom moves IDs to the bottom of the queue list.
 func (c *Client) QueueMoveBottom(ctx context.Context, IDs []int64) (err error) {
 	payload := &queueMovePayload{IDs: IDs}
-	if err = c.rpcCall(ctx, "queue-move-bottom", payload, nil); err != nil {
+	if err = c.rpcCall(ctx, MethodQueueMoveBottom, payload, nil); err != nil {
 		err = fmt.Errorf("'queue-move-bottom' rpc method failed: %w", err)
 	}
 	return
 }
 
+// QueueMovement is a queue movement RPC method, see QueueMoveSelection().
+type QueueMovement string
+
+// Queue movements
+const (
+	QueueTop    QueueMovement = MethodQueueMoveTop
+	QueueUp     QueueMovement = MethodQueueMoveUp
+	QueueDown   QueueMovement = MethodQueueMoveDown
+	QueueBottom QueueMovement = MethodQueueMoveBottom
+)
+
+// QueueMoveSelection moves the torrents of selector within their queue.
+func (c *Client) QueueMoveSelection(ctx context.Context, movement QueueMovement, selector TorrentSelector) (err error) {
+	switch movement {
+	case QueueTop, QueueUp, QueueDown, QueueBottom:
+	default:
+		return fmt.Errorf("invalid queue movement '%s'", movement)
+	}
+	return c.torrentActionSelector(ctx, string(movement), selector)
+}
+
+// TorrentSetQueuePosition moves a torrent to an absolute position [0...n) of its queue.
+func (c *Client) TorrentSetQueuePosition(ctx context.Context, id int64, position int64) (err error) {
+	if position < 0 {
+		return fmt.Errorf("queue position can't be negative: %d", position)
+	}
+	return c.TorrentSet(ctx, TorrentSetPayload{
+		IDs:           []int64{id},
+		QueuePosition: &position,
+	})
+}
+
+// QueueCompact reassigns contiguous queue positions (0 to n-1) to the torrents, preserving their relative order
+// (torrents sharing a position are ordered by id). Nothing is sent if the positions are already contiguous.
+func (c *Client) QueueCompact(ctx context.Context) (err error) {
+	torrents, err := c.fetchTorrents(ctx, []string{"id", "queuePosition"}, nil)
+	if err != nil {
+		return
+	}
+	queue := make([]Torrent, 0, len(torrents))
+	for _, torrent := range torrents {
+		if torrent.ID != nil && torrent.QueuePosition != nil {
+			queue = append(queue, torrent)
+		}
+	}
+	sort.Slice(queue, func(i, j int) bool {
+		if *queue[i].QueuePosition != *queue[j].QueuePosition {
+			return *queue[i].QueuePosition < *queue[j].QueuePosition
+		}
+		return *queue[i].ID < *queue[j].ID
+	})
+	// Placing them in order from the top keeps the already placed ones untouched
+	for position, torrent := range queue {
+		if *torrent.QueuePosition == int64(position) {
+			continue
+		}
+		if err = c.TorrentSetQueuePosition(ctx, *torrent.ID, int64(position)); err != nil {
+			return fmt.Errorf("can't move torrent %d to queue position %d: %w", *torrent.ID, position, err)
+		}
+	}
+	return
+}
+
+// TorrentPromote sets the bandwidth priority of a torrent to high and moves it to the top of its queue, within a single torrent-set.
+func (c *Client) TorrentPromote(ctx context.Context, id int64) (err error) {
+	priority := int64(1) // TR_PRI_HIGH
+	position := int64(0)
+	return c.TorrentSet(ctx, TorrentSetPayload{
+		IDs:               []int64{id},
+		BandwidthPriority: &priority,
+		QueuePosition:     &position,
+	})
+}
+
+// TorrentDemote sets the bandwidth priority of a torrent to low and moves it to the bottom of its queue.
+// The bottom position is not known without counting the torrents: this costs a queue-move-bottom call after the torrent-set.
+func (c *Client) TorrentDemote(ctx context.Context, id int64) (err error) {
+	priority := int64(-1) // TR_PRI_LOW
+	if err = c.TorrentSet(ctx, TorrentSetPayload{
+		IDs:               []int64{id},
+		BandwidthPriority: &priority,
+	}); err != nil {
+		return
+	}
+	return c.QueueMoveBottom(ctx, []int64{id})
+}
+
 type queueMovePayload struct {
 	IDs []int64 `json:"ids"`
 }
+
+// WaitForQueueIdle blocks until no torrent is downloading or waiting to download, polling the torrents status every poll
+// (1 second if poll is not positive). It returns the context error if ctx is done before.
+func (c *Client) WaitForQueueIdle(ctx context.Context, poll time.Duration) (err error) {
+	if poll <= 0 {
+		poll = time.Second
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	var torrents []Torrent
+	for {
+		if torrents, err = c.fetchTorrents(ctx, []string{"id", "status"}, nil); err != nil {
+			return
+		}
+		idle := true
+		for _, torrent := range torrents {
+			if torrent.Status != nil && (*torrent.Status == TorrentStatusDownload || *torrent.Status == TorrentStatusDownloadWait) {
+				idle = false
+				break
+			}
+		}
+		if idle {
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package transmissionrpc
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket limiting the sustained rate of the RPC calls, see Config.RateLimit.
+type rateLimiter struct {
+	rate   float64 // tokens per second, 0 disables the limiter
+	burst  float64
+	tokens float64
+	last   time.Time
+	access sync.Mutex
+}
+
+func newRateLimiter(rate float64, burst int) (rl *rateLimiter) {
+	rl = &rateLimiter{rate: rate}
+	if rate <= 0 {
+		return
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	rl.burst = float64(burst)
+	rl.tokens = rl.burst
+	rl.last = time.Now()
+	return
+}
+
+// wait blocks until a token is available or ctx is done.
+func (rl *rateLimiter) wait(ctx context.Context) (err error) {
+	if rl == nil || rl.rate <= 0 {
+		return
+	}
+	var delay time.Duration
+	for {
+		if delay = rl.take(); delay == 0 {
+			return
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// take consumes a token if available, otherwise it returns the delay before the next one.
+func (rl *rateLimiter) take() (delay time.Duration) {
+	defer rl.access.Unlock()
+	rl.access.Lock()
+	now := time.Now()
+	if rl.tokens += now.Sub(rl.last).Seconds() * rl.rate; rl.tokens > rl.burst {
+		rl.tokens = rl.burst
+	}
+	rl.last = now
+	if rl.tokens >= 1 {
+		rl.tokens--
+		return
+	}
+	if delay = time.Duration((1 - rl.tokens) / rl.rate * float64(time.Second)); delay <= 0 {
+		delay = time.Millisecond
+	}
+	return
+}
@@ -6,7 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const csrfHeader = "X-Transmission-Session-Id"
@@ -24,6 +30,48 @@ type answerPayload struct {
 }
 
 func (c *Client) rpcCall(ctx context.Context, method string, arguments interface{}, result interface{}) (err error) {
+	start := time.Now()
+	err = c.retryCall(ctx, method, arguments, result)
+	c.latencies.record(method, time.Since(start))
+	c.updateConnectionState(ctx, err)
+	if c.settings.AuditLog != nil && !readOnlyMethods[method] {
+		c.audit(start, method, arguments, err)
+	}
+	return
+}
+
+func (c *Client) call(ctx context.Context, method string, arguments interface{}, result interface{}) (err error) {
+	if err = c.rateLimiter.wait(ctx); err != nil {
+		return fmt.Errorf("rate limit wait failed: %w", err)
+	}
+	if err = c.limitedRequest(ctx, method, arguments, result); err == nil {
+		return
+	}
+	// Server asked to come back later ?
+	var unavailable retryAfterError
+	if !errors.As(err, &unavailable) || unavailable.delay > c.maxRetryAfter {
+		return
+	}
+	timer := time.NewTimer(unavailable.delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return fmt.Errorf("%w (while waiting %v as asked by Retry-After)", ctx.Err(), unavailable.delay)
+	}
+	return c.limitedRequest(ctx, method, arguments, result)
+}
+
+// limitedRequest sends the request once a slot is available, see Config.MaxConcurrentRPC.
+func (c *Client) limitedRequest(ctx context.Context, method string, arguments interface{}, result interface{}) (err error) {
+	if c.rpcSlots != nil {
+		select {
+		case c.rpcSlots <- struct{}{}:
+			defer func() { <-c.rpcSlots }()
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for a free RPC slot failed: %w", ctx.Err())
+		}
+	}
 	return c.request(ctx, method, arguments, result, true)
 }
 
@@ -44,15 +92,31 @@ func (c *Client) request(ctx context.Context, method string, arguments interface
 		err = fmt.Errorf("failed to marshal request payload: %w", err)
 		return
 	}
+	stats := callStatsFromContext(ctx)
+	if stats != nil {
+		stats.requestSize = int64(len(rqJSON))
+		stats.responseSize = 0
+	}
 	// Build the request
 	var req *http.Request
 	if req, err = http.NewRequestWithContext(ctx, "POST", c.endpoint.String(), bytes.NewBuffer(rqJSON)); err != nil {
 		err = fmt.Errorf("can't prepare request for '%s' method: %w", method, err)
 		return
 	}
+	for key, values := range c.headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", c.userAgent)
-	req.Header.Set(csrfHeader, c.getSessionID())
+	sessionID, negotiation, err := c.sessionID.acquire(ctx)
+	if err != nil {
+		err = fmt.Errorf("waiting for the session id negotiation failed: %w", err)
+		return
+	}
+	defer c.sessionID.release(negotiation)
+	req.Header.Set(csrfHeader, sessionID)
 	// Execute request
 	var resp *http.Response
 	if resp, err = c.http.Do(req); err != nil {
@@ -60,27 +124,65 @@ func (c *Client) request(ctx context.Context, method string, arguments interface
 		return
 	}
 	defer resp.Body.Close()
-	// Is the CRSF token invalid ?
+	// Is the CRSF token invalid ? The daemon rotated it (or this is the first request): the new one is within the answer
 	if resp.StatusCode == http.StatusConflict {
 		// Recover new token and save it
-		c.updateSessionID(resp.Header.Get(csrfHeader))
-		// Retry request if first try
+		if sessionID = resp.Header.Get(csrfHeader); sessionID == "" {
+			err = fmt.Errorf("%w: answer does not contain a new '%s' header", HTTPStatusCode(resp.StatusCode), csrfHeader)
+			return
+		}
+		c.updateSessionID(sessionID)
+		// Retry request (once) with the new token
 		if retry {
+			_, _ = io.Copy(io.Discard, resp.Body) // allow the connection to be reused
 			return c.request(ctx, method, arguments, result, false)
 		}
-		err = errors.New("CSRF token invalid 2 times in a row: stopping to avoid infinite loop")
+		err = fmt.Errorf("%w: CSRF token invalid 2 times in a row: stopping to avoid infinite loop", HTTPStatusCode(resp.StatusCode))
 		return
 	}
+	c.sessionID.accepted(sessionID)
+	// Is the server temporarily unavailable ?
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			err = retryAfterError{status: HTTPStatusCode(resp.StatusCode), delay: delay}
+			return
+		}
+	}
 	// Is request successful ?
 	if resp.StatusCode != 200 {
 		err = HTTPStatusCode(resp.StatusCode)
 		return
 	}
 	// Decode body
+	var body io.Reader = resp.Body
+	if stats != nil {
+		body = countingReader{reader: resp.Body, count: &stats.responseSize}
+	}
 	answer := answerPayload{
 		Arguments: result,
 	}
-	if err = json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+	if streamer, ok := result.(argumentsStreamer); ok {
+		// Decode arguments incrementally, raw arguments are not kept
+		if err = decodeStreamedAnswer(json.NewDecoder(body), &answer, streamer); err != nil {
+			err = fmt.Errorf("can't unmarshal request answer body: %w", err)
+			return
+		}
+	} else if c.keepLastRaw {
+		// Decode arguments in two steps in order to keep them raw
+		var raw json.RawMessage
+		answer.Arguments = &raw
+		if err = json.NewDecoder(body).Decode(&answer); err != nil {
+			err = fmt.Errorf("can't unmarshal request answer body: %w", err)
+			return
+		}
+		c.updateLastRaw(raw)
+		if result != nil && len(raw) > 0 {
+			if err = json.Unmarshal(raw, result); err != nil {
+				err = fmt.Errorf("can't unmarshal request answer arguments: %w", err)
+				return
+			}
+		}
+	} else if err = json.NewDecoder(body).Decode(&answer); err != nil {
 		err = fmt.Errorf("can't unmarshal request answer body: %w", err)
 		return
 	}
@@ -94,13 +196,73 @@ func (c *Client) request(ctx context.Context, method string, arguments interface
 		return
 	}
 	if answer.Result != "success" {
-		err = fmt.Errorf("http request ok but payload does not indicate success: %s", answer.Result)
+		err = RPCError{
+			Method:     method,
+			Result:     answer.Result,
+			HTTPStatus: resp.StatusCode,
+		}
 		return
 	}
 	// All good
 	return
 }
 
+// isTransientError returns true if err is likely caused by a temporary condition
+// (network failure or server side error) and the request result is unknown.
+func isTransientError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var statusCode HTTPStatusCode
+	if errors.As(err, &statusCode) {
+		return statusCode >= 500
+	}
+	return false
+}
+
+// retryAfterError is returned by request() when the server answered 503 with a valid Retry-After header.
+type retryAfterError struct {
+	status HTTPStatusCode
+	delay  time.Duration
+}
+
+func (rae retryAfterError) Error() string {
+	return fmt.Sprintf("%v (retry after %v)", rae.status, rae.delay)
+}
+
+func (rae retryAfterError) Unwrap() error {
+	return rae.status
+}
+
+// parseRetryAfter parses a Retry-After header value, either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (delay time.Duration, ok bool) {
+	if value = strings.TrimSpace(value); value == "" {
+		return
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return
+		}
+		if seconds > int64(math.MaxInt64/time.Second) {
+			return math.MaxInt64, true // would overflow: longer than any acceptable delay anyway
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return
+	}
+	if delay = date.Sub(now); delay < 0 {
+		delay = 0
+	}
+	ok = true
+	return
+}
+
 // HTTPStatusCode is a custom error type for HTTP errors
 type HTTPStatusCode int
 
@@ -0,0 +1,325 @@
+package transmissionrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubRequest is a request received by a stub daemon.
+type stubRequest struct {
+	Method    string          `json:"method"`
+	Arguments json.RawMessage `json:"arguments"`
+	Tag       int             `json:"tag"`
+}
+
+func readStubRequest(t *testing.T, r *http.Request) (rq stubRequest) {
+	t.Helper()
+	if err := json.NewDecoder(r.Body).Decode(&rq); err != nil {
+		t.Errorf("can't decode request: %v", err)
+	}
+	return
+}
+
+// writeStubAnswer answers a successful result with arguments.
+func writeStubAnswer(t *testing.T, w http.ResponseWriter, rq stubRequest, arguments interface{}) {
+	t.Helper()
+	if arguments == nil {
+		arguments = struct{}{}
+	}
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"arguments": arguments,
+		"result":    "success",
+		"tag":       rq.Tag,
+	}); err != nil {
+		t.Errorf("can't encode answer: %v", err)
+	}
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+// newStubClient returns a client of a stub daemon served by handler, closed at the end of the test.
+func newStubClient(t *testing.T, extra *Config, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := New(mustParseURL(t, server.URL), extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestRetryAfterIsHonored(t *testing.T) {
+	var calls int32
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeStubAnswer(t, w, rq, nil)
+	})
+	start := time.Now()
+	if _, err := client.SessionStats(context.Background()); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, before the asked 1s", elapsed)
+	}
+	if calls := atomic.LoadInt32(&calls); calls != 2 {
+		t.Errorf("got %d requests, want 2", calls)
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	var calls int32
+	client := newStubClient(t, &Config{MaxRetryAfter: time.Second}, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	start := time.Now()
+	_, err := client.SessionStats(context.Background())
+	var status HTTPStatusCode
+	if !errors.As(err, &status) || status != http.StatusServiceUnavailable {
+		t.Fatalf("got error %v, want HTTP 503", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("waited %v instead of failing immediately", elapsed)
+	}
+	if calls := atomic.LoadInt32(&calls); calls != 1 {
+		t.Errorf("got %d requests, want 1", calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		delay time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{" 0 ", 0, true},
+		{"-1", 0, false},
+		{"10000000000", math.MaxInt64, true},
+		{"9223372036854775807", math.MaxInt64, true},
+		{"soon", 0, false},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	}
+	for _, test := range tests {
+		delay, ok := parseRetryAfter(test.value, now)
+		if delay != test.delay || ok != test.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", test.value, delay, ok, test.delay, test.ok)
+		}
+	}
+}
+
+// sessionIDHandler rejects the requests without the current session id with a 409 carrying it.
+func sessionIDHandler(t *testing.T, current func() string, conflicts *int32, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if id := current(); r.Header.Get(csrfHeader) != id {
+			atomic.AddInt32(conflicts, 1)
+			w.Header().Set(csrfHeader, id)
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func TestSessionIDRenewal(t *testing.T) {
+	var conflicts, sets int32
+	client := newStubClient(t, nil, sessionIDHandler(t, func() string { return "token" }, &conflicts,
+		func(w http.ResponseWriter, r *http.Request) {
+			rq := readStubRequest(t, r)
+			if rq.Method == MethodTorrentSet {
+				atomic.AddInt32(&sets, 1)
+			}
+			writeStubAnswer(t, w, rq, nil)
+		}))
+	for i := 0; i < 2; i++ {
+		if err := client.TorrentSet(context.Background(), NewTorrentSetPayload([]int64{1}, WithUploadLimit(5))); err != nil {
+			t.Fatalf("TorrentSet() failed: %v", err)
+		}
+	}
+	if conflicts := atomic.LoadInt32(&conflicts); conflicts != 1 {
+		t.Errorf("got %d 409 answers, want 1 (the session id must be kept)", conflicts)
+	}
+	if sets := atomic.LoadInt32(&sets); sets != 2 {
+		t.Errorf("got %d accepted torrent-set, want 2", sets)
+	}
+}
+
+func TestSessionIDConflictTwice(t *testing.T) {
+	var conflicts, calls int32
+	client := newStubClient(t, nil, sessionIDHandler(t, func() string {
+		// rotated for each request
+		return fmt.Sprintf("token-%d", atomic.AddInt32(&calls, 1))
+	}, &conflicts, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request accepted with a stale session id")
+	}))
+	err := client.TorrentSet(context.Background(), NewTorrentSetPayload([]int64{1}, WithUploadLimit(5)))
+	if !errors.Is(err, ErrSessionIDRenewal) {
+		t.Errorf("got error %v, want ErrSessionIDRenewal", err)
+	}
+	if conflicts := atomic.LoadInt32(&conflicts); conflicts != 2 {
+		t.Errorf("got %d requests, want 2 (no loop)", conflicts)
+	}
+}
+
+func TestSessionIDNegotiatedOnceForConcurrentCalls(t *testing.T) {
+	var conflicts int32
+	client := newStubClient(t, nil, sessionIDHandler(t, func() string { return "token" }, &conflicts,
+		func(w http.ResponseWriter, r *http.Request) {
+			writeStubAnswer(t, w, readStubRequest(t, r), nil)
+		}))
+	var workers sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if _, err := client.SessionStats(context.Background()); err != nil {
+				t.Errorf("call failed: %v", err)
+			}
+		}()
+	}
+	workers.Wait()
+	if conflicts := atomic.LoadInt32(&conflicts); conflicts != 1 {
+		t.Errorf("got %d 409 answers, want 1", conflicts)
+	}
+}
+
+func TestSessionIDNotRequired(t *testing.T) {
+	var calls int32
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeStubAnswer(t, w, readStubRequest(t, r), nil)
+	})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var workers sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			workers.Add(1)
+			go func() {
+				defer workers.Done()
+				if _, err := client.SessionStats(context.Background()); err != nil {
+					t.Errorf("call failed: %v", err)
+				}
+			}()
+		}
+		workers.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("calls blocked waiting for a session id the daemon never asked for")
+	}
+	if calls := atomic.LoadInt32(&calls); calls != 10 {
+		t.Errorf("got %d requests, want 10", calls)
+	}
+}
+
+// peakTransport answers the requests itself after a delay, recording the peak number of requests in flight.
+type peakTransport struct {
+	delay    time.Duration
+	inFlight int32
+	peak     int32
+	release  chan struct{} // if not nil, requests are blocked until it is closed
+}
+
+func (pt *peakTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	current := atomic.AddInt32(&pt.inFlight, 1)
+	defer atomic.AddInt32(&pt.inFlight, -1)
+	for {
+		peak := atomic.LoadInt32(&pt.peak)
+		if current <= peak || atomic.CompareAndSwapInt32(&pt.peak, peak, current) {
+			break
+		}
+	}
+	if pt.release != nil {
+		<-pt.release
+	}
+	time.Sleep(pt.delay)
+	var rq stubRequest
+	if err = json.NewDecoder(req.Body).Decode(&rq); err != nil {
+		return
+	}
+	recorder := httptest.NewRecorder()
+	recorder.Body.WriteString(fmt.Sprintf(`{"arguments":{},"result":"success","tag":%d}`, rq.Tag))
+	resp = recorder.Result()
+	resp.Request = req
+	return
+}
+
+func TestMaxConcurrentRPC(t *testing.T) {
+	transport := &peakTransport{delay: 10 * time.Millisecond}
+	client, err := New(mustParseURL(t, "http://daemon/transmission/rpc"), &Config{
+		CustomClient:     &http.Client{Transport: transport},
+		MaxConcurrentRPC: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var workers sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			if _, err := client.SessionStats(context.Background()); err != nil {
+				t.Errorf("call failed: %v", err)
+			}
+		}()
+	}
+	workers.Wait()
+	if peak := atomic.LoadInt32(&transport.peak); peak > 3 || peak < 1 {
+		t.Errorf("got a peak of %d requests in flight, want at most 3", peak)
+	}
+}
+
+func TestMaxConcurrentRPCWaitCancelled(t *testing.T) {
+	transport := &peakTransport{release: make(chan struct{})}
+	defer close(transport.release)
+	client, err := New(mustParseURL(t, "http://daemon/transmission/rpc"), &Config{
+		CustomClient:     &http.Client{Transport: transport},
+		MaxConcurrentRPC: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_, _ = client.SessionStats(context.Background()) // holds the only slot
+	}()
+	for atomic.LoadInt32(&transport.inFlight) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err = client.SessionStats(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want the context error", err)
+	}
+	if inFlight := atomic.LoadInt32(&transport.inFlight); inFlight != 1 {
+		t.Errorf("got %d requests in flight, want 1", inFlight)
+	}
+}
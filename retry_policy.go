@@ -0,0 +1,107 @@
+package transmissionrpc
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+/*
+	Retry policy
+	Automatic retry of the idempotent calls, see Config.Retry
+*/
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryBaseDelay   = 250 * time.Millisecond
+	defaultRetryMaxDelay    = 10 * time.Second
+)
+
+// RetryPolicy configures the automatic retry of the idempotent calls (reads, actions, torrent-set, session-set, etc.),
+// see Config.Retry. torrent-add, torrent-remove, torrent-rename-path, torrent-set-location, relative queue moves and
+// session-close are never retried as their result would differ when sent twice.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts of a call, the first one included. Defaults to 3.
+	MaxAttempts int
+	// Backoff (optional) returns the delay to wait before the given retry (starting at 1).
+	// Defaults to an exponential backoff starting at 250ms and capped at 10 seconds.
+	Backoff func(retry int) time.Duration
+	// RetryOn (optional) returns true if the call failure is worth a retry. Defaults to the transient errors
+	// (network errors, server side HTTP errors) and to the session id renewal failures (ErrSessionIDRenewal):
+	// a new session id is then fetched by the next attempt.
+	RetryOn func(err error) bool
+}
+
+// retryableMethods are the RPC methods producing the same result when sent several times.
+var retryableMethods = map[string]bool{
+	MethodTorrentStart:      true,
+	MethodTorrentStartNow:   true,
+	MethodTorrentStop:       true,
+	MethodTorrentVerify:     true,
+	MethodTorrentReannounce: true,
+	MethodTorrentSet:        true,
+	MethodTorrentGet:        true,
+	MethodSessionSet:        true,
+	MethodSessionGet:        true,
+	MethodSessionStats:      true,
+	MethodBlocklistUpdate:   true,
+	MethodPortTest:          true,
+	MethodQueueMoveTop:      true,
+	MethodQueueMoveBottom:   true,
+	MethodFreeSpace:         true,
+	MethodGroupSet:          true,
+	MethodGroupGet:          true,
+}
+
+func (rp *RetryPolicy) maxAttempts() int {
+	if rp.MaxAttempts < 1 {
+		return defaultRetryMaxAttempts
+	}
+	return rp.MaxAttempts
+}
+
+func (rp *RetryPolicy) delay(retry int) (delay time.Duration) {
+	if rp.Backoff != nil {
+		return rp.Backoff(retry)
+	}
+	delay = defaultRetryBaseDelay
+	for i := 1; i < retry && delay < defaultRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > defaultRetryMaxDelay {
+		delay = defaultRetryMaxDelay
+	}
+	return
+}
+
+func (rp *RetryPolicy) shouldRetry(err error) bool {
+	if rp.RetryOn != nil {
+		return rp.RetryOn(err)
+	}
+	return isTransientError(err) || errors.Is(err, ErrSessionIDRenewal)
+}
+
+// retryCall sends the call and retries it according to the client retry policy (if any and if the method is retryable).
+// Retries are given up if ctx is done, or if its deadline would expire during the backoff.
+func (c *Client) retryCall(ctx context.Context, method string, arguments interface{}, result interface{}) (err error) {
+	policy := c.settings.Retry
+	err = c.callAttempt(ctx, method, 1, arguments, result)
+	if policy == nil || !retryableMethods[method] {
+		return
+	}
+	for retry := 1; retry < policy.maxAttempts() && err != nil && ctx.Err() == nil && policy.shouldRetry(err); retry++ {
+		delay := policy.delay(retry)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+		err = c.callAttempt(ctx, method, retry+1, arguments, result)
+	}
+	return
+}
@@ -0,0 +1,112 @@
+package transmissionrpc
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+	RPC versions
+    https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#5-protocol-versions
+*/
+
+// torrentFieldsMinRPCVersion lists the torrent-get fields which are not available on every RPC version.
+// Fields not listed here are considered available since RPC v15 (the oldest version supported by this library family).
+var torrentFieldsMinRPCVersion = map[string]int64{
+	"availability":      17,
+	"editDate":          16,
+	"file-count":        17,
+	"group":             17,
+	"labels":            16,
+	"percentComplete":   17,
+	"primary-mime-type": 17,
+	"trackerList":       17,
+}
+
+// serverVersion caches the capabilities of the remote server, fetched on first use.
+type serverVersion struct {
+	capabilities *ServerCapabilities
+	fetching     chan struct{} // closed once the fetching call is done
+	generation   uint64        // incremented by reset(): a fetch started before is not cached
+	access       sync.Mutex
+}
+
+// reset drops the cached capabilities, to be fetched again by the next call.
+func (sv *serverVersion) reset() {
+	defer sv.access.Unlock()
+	sv.access.Lock()
+	sv.capabilities = nil
+	sv.generation++
+}
+
+// ServerRPCVersion returns the RPC version of the remote server. It is fetched with session-get on first call and cached afterwards.
+func (c *Client) ServerRPCVersion(ctx context.Context) (version int64, err error) {
+	capabilities, err := c.ServerCapabilities(ctx)
+	if err != nil {
+		return
+	}
+	version = capabilities.RPCVersion
+	return
+}
+
+// SupportedTorrentFields splits fields between the ones supported by the given RPC version and the others.
+func SupportedTorrentFields(fields []string, rpcVersion int64) (supported, unsupported []string) {
+	supported = make([]string, 0, len(fields))
+	for _, field := range fields {
+		if minVersion, versioned := torrentFieldsMinRPCVersion[field]; versioned && rpcVersion < minVersion {
+			unsupported = append(unsupported, field)
+		} else {
+			supported = append(supported, field)
+		}
+	}
+	return
+}
+
+// TorrentGetCompatible does the same as TorrentGet but first drops the fields the remote server RPC version does not know
+// (see ServerRPCVersion()) instead of letting the whole call fail. The dropped fields are returned as well.
+func (c *Client) TorrentGetCompatible(ctx context.Context, fields []string, ids []int64) (torrents []Torrent, dropped []string, err error) {
+	if err = c.validateTorrentFields(fields); err != nil {
+		return
+	}
+	version, err := c.ServerRPCVersion(ctx)
+	if err != nil {
+		return
+	}
+	fields, dropped = SupportedTorrentFields(fields, version)
+	torrents, err = c.torrentGet(ctx, fields, ids)
+	return
+}
+
+// FeatureSet lists the optional features supported by the remote server, see Client.Features().
+type FeatureSet struct {
+	RPCVersion                  int64
+	LabelsSupported             bool // RPC v16: torrents labels
+	TableFormatSupported        bool // RPC v16: torrent-get "table" format
+	BandwidthGroupsSupported    bool // RPC v17: group-get/group-set and torrents group field
+	TrackerListSupported        bool // RPC v17: torrent-get/torrent-set trackerList field
+	FreeSpaceTotalSizeSupported bool // RPC v17: free-space total_size field
+	SequentialDownloadSupported bool // RPC v18: torrents sequential download
+}
+
+// FeaturesForRPCVersion returns the features available for a given RPC version.
+func FeaturesForRPCVersion(rpcVersion int64) FeatureSet {
+	return FeatureSet{
+		RPCVersion:                  rpcVersion,
+		LabelsSupported:             rpcVersion >= 16,
+		TableFormatSupported:        rpcVersion >= 16,
+		BandwidthGroupsSupported:    rpcVersion >= 17,
+		TrackerListSupported:        rpcVersion >= 17,
+		FreeSpaceTotalSizeSupported: rpcVersion >= 17,
+		SequentialDownloadSupported: rpcVersion >= 18,
+	}
+}
+
+// Features returns the optional features supported by the remote server, derived from its RPC version (see ServerRPCVersion()).
+func (c *Client) Features(ctx context.Context) (features FeatureSet, err error) {
+	version, err := c.ServerRPCVersion(ctx)
+	if err != nil {
+		return
+	}
+	features = FeaturesForRPCVersion(version)
+	return
+}
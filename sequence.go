@@ -0,0 +1,116 @@
+package transmissionrpc
+
+import (
+	"context"
+	"fmt"
+)
+
+/*
+	Sequences
+	Ordered execution of several actions and mutators with fail-fast semantic
+*/
+
+// Sequence is an ordered list of actions and mutators to execute against the daemon.
+// It must be created with Client.NewSequence() and executed with Run().
+type Sequence struct {
+	client *Client
+	steps  []sequenceStep
+}
+
+type sequenceStep struct {
+	name string
+	fx   func(ctx context.Context) error
+}
+
+// SequenceError is returned by Sequence.Run() when a step fails. Next steps are not executed.
+type SequenceError struct {
+	Step int    // index of the failed step
+	Name string // name of the failed step
+	Err  error
+}
+
+func (se SequenceError) Error() string {
+	return fmt.Sprintf("sequence step %d (%s) failed: %v", se.Step, se.Name, se.Err)
+}
+
+// Unwrap returns the underlying step error.
+func (se SequenceError) Unwrap() error {
+	return se.Err
+}
+
+// NewSequence returns an empty sequence bound to the client.
+func (c *Client) NewSequence() *Sequence {
+	return &Sequence{client: c}
+}
+
+func (s *Sequence) add(name string, fx func(ctx context.Context) error) *Sequence {
+	s.steps = append(s.steps, sequenceStep{name: name, fx: fx})
+	return s
+}
+
+// Start adds a torrent-start step.
+func (s *Sequence) Start(ids []int64) *Sequence {
+	return s.add("start", func(ctx context.Context) error {
+		return s.client.TorrentStartIDs(ctx, ids)
+	})
+}
+
+// StartNow adds a torrent-start-now step.
+func (s *Sequence) StartNow(ids []int64) *Sequence {
+	return s.add("start now", func(ctx context.Context) error {
+		return s.client.TorrentStartNowIDs(ctx, ids)
+	})
+}
+
+// Stop adds a torrent-stop step.
+func (s *Sequence) Stop(ids []int64) *Sequence {
+	return s.add("stop", func(ctx context.Context) error {
+		return s.client.TorrentStopIDs(ctx, ids)
+	})
+}
+
+// Verify adds a torrent-verify step.
+func (s *Sequence) Verify(ids []int64) *Sequence {
+	return s.add("verify", func(ctx context.Context) error {
+		return s.client.TorrentVerifyIDs(ctx, ids)
+	})
+}
+
+// Reannounce adds a torrent-reannounce step.
+func (s *Sequence) Reannounce(ids []int64) *Sequence {
+	return s.add("reannounce", func(ctx context.Context) error {
+		return s.client.TorrentReannounceIDs(ctx, ids)
+	})
+}
+
+// SetLocation adds a torrent-set-location step.
+// 'move' if true, move from previous location. Otherwise, search "location" for file.
+func (s *Sequence) SetLocation(ids []int64, location string, move bool) *Sequence {
+	return s.add("set location", func(ctx context.Context) error {
+		return s.client.TorrentSetLocationSelection(ctx, IDs(ids...), location, move)
+	})
+}
+
+// Set adds a torrent-set step.
+func (s *Sequence) Set(payload TorrentSetPayload) *Sequence {
+	return s.add("set", func(ctx context.Context) error {
+		return s.client.TorrentSet(ctx, payload)
+	})
+}
+
+// Run executes each step in order and stops at the first error, returned as a SequenceError.
+func (s *Sequence) Run(ctx context.Context) (err error) {
+	for index, step := range s.steps {
+		if err = ctx.Err(); err == nil {
+			err = step.fx(ctx)
+		}
+		if err != nil {
+			return SequenceError{
+				Step: index,
+				Name: step.name,
+				Err:  err,
+			}
+		}
+	}
+	return
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/hekmon/cunits/v2"
@@ -26,20 +27,29 @@ func init() {
 	}
 }
 
+// Encryption is the peer connections encryption mode of the daemon.
 type Encryption string
 
 const (
-	EncryptionRequired  Encryption = "required"
-	EncryptionPreferred Encryption = "prefered"
+	// EncryptionRequired only accepts encrypted connections
+	EncryptionRequired Encryption = "required"
+	// EncryptionPreferred prefers encrypted connections
+	EncryptionPreferred Encryption = "preferred"
+	// EncryptionTolerated prefers unencrypted connections
 	EncryptionTolerated Encryption = "tolerated"
 )
 
+// IsValid returns true if the encryption mode is one of the Encryption constants.
+func (e Encryption) IsValid() bool {
+	return e == EncryptionRequired || e == EncryptionPreferred || e == EncryptionTolerated
+}
+
 // SessionArguments represents all the global/session values.
 type SessionArguments struct {
 	AltSpeedDown                     *int64      `json:"alt-speed-down"`                       // max global download speed (KBps)
 	AltSpeedEnabled                  *bool       `json:"alt-speed-enabled"`                    // true means use the alt speeds
 	AltSpeedTimeBegin                *int64      `json:"alt-speed-time-begin"`                 // when to turn on alt speeds (units: minutes after midnight)
-	AltSpeedTimeDay                  *int64      `json:"alt-speed-time-day"`                   // what day(s) to turn on alt speeds (look at tr_sched_day)
+	AltSpeedTimeDay                  *int64      `json:"alt-speed-time-day"`                   // what day(s) to turn on alt speeds (see AltSpeedDay)
 	AltSpeedTimeEnabled              *bool       `json:"alt-speed-time-enabled"`               // true means the scheduled on/off times are used
 	AltSpeedTimeEnd                  *int64      `json:"alt-speed-time-end"`                   // when to turn off alt speeds (units: same)
 	AltSpeedUp                       *int64      `json:"alt-speed-up"`                         // max global upload speed (KBps)
@@ -60,7 +70,7 @@ type SessionArguments struct {
 	IncompleteDir                    *string     `json:"incomplete-dir"`                       // path for incomplete torrents, when enabled
 	LPDEnabled                       *bool       `json:"lpd-enabled"`                          // true means allow Local Peer Discovery in public torrents
 	PeerLimitGlobal                  *int64      `json:"peer-limit-global"`                    // maximum global number of peers
-	PeerLimitPerTorrent              *int64      `json:"peer-limit-per-torrent"`               // maximum global number of peers
+	PeerLimitPerTorrent              *int64      `json:"peer-limit-per-torrent"`               // maximum number of peers per torrent
 	PeerPortRandomOnStart            *bool       `json:"peer-port-random-on-start"`            // true means pick a random peer port on launch
 	PeerPort                         *int64      `json:"peer-port"`                            // port number
 	PEXEnabled                       *bool       `json:"pex-enabled"`                          // true means allow pex in public torrents
@@ -68,9 +78,13 @@ type SessionArguments struct {
 	QueueStalledEnabled              *bool       `json:"queue-stalled-enabled"`                // whether or not to consider idle torrents as stalled
 	QueueStalledMinutes              *int64      `json:"queue-stalled-minutes"`                // torrents that are idle for N minuets aren't counted toward seed-queue-size or download-queue-size
 	RenamePartialFiles               *bool       `json:"rename-partial-files"`                 // true means append ".part" to incomplete files
+	RPCHostWhitelist                 *string     `json:"rpc-host-whitelist"`                   // comma-separated list of the host names accepted by the RPC server (not exposed by every daemon)
+	RPCHostWhitelistEnabled          *bool       `json:"rpc-host-whitelist-enabled"`           // true means the RPC host whitelist is enforced (not exposed by every daemon)
 	RPCVersionMinimum                *int64      `json:"rpc-version-minimum"`                  // the minimum RPC API version supported
 	RPCVersionSemVer                 *string     `json:"rpc-version-semver"`                   // the current RPC API version in a semver-compatible string
 	RPCVersion                       *int64      `json:"rpc-version"`                          // the current RPC API version
+	RPCWhitelist                     *string     `json:"rpc-whitelist"`                        // comma-separated list of the IP addresses (wildcards allowed) accepted by the RPC server (not exposed by every daemon)
+	RPCWhitelistEnabled              *bool       `json:"rpc-whitelist-enabled"`                // true means the RPC whitelist is enforced (not exposed by every daemon)
 	ScriptTorrentAddedEnabled        *bool       `json:"script-torrent-added-enabled"`         // whether or not to call the added script
 	ScriptTorrentAddedFilename       *string     `json:"script-torrent-added-filename"`        //filename of the script to run
 	ScriptTorrentDoneEnabled         *bool       `json:"script-torrent-done-enabled"`          // whether or not to call the "done" script
@@ -97,50 +111,72 @@ type SessionArguments struct {
 // It differs from 'omitempty' which also skip default values
 // (as 0 or false which can be valid here).
 func (sa SessionArguments) MarshalJSON() (data []byte, err error) {
-	// Build an intermediary payload with base types
-	type baseSessionArguments SessionArguments
-	tmp := struct {
-		DefaultTrackers *string `json:"default-trackers"` // list of default trackers to use on public torrents
-		*baseSessionArguments
-	}{
-		baseSessionArguments: (*baseSessionArguments)(&sa),
-	}
-	if sa.DefaultTrackers != nil {
-		oneLineDefaultTrackers := strings.Join(sa.DefaultTrackers, "\n")
-		tmp.DefaultTrackers = &oneLineDefaultTrackers
-	}
-	// Build a payload with only the non nil fields
-	sav := reflect.ValueOf(tmp)
-	sat := sav.Type()
-	cleanPayload := make(map[string]interface{}, sat.NumField())
-	var currentValue, nestedStruct, currentNestedValue reflect.Value
-	var currentStructField, currentNestedStructField reflect.StructField
-	var j int
-	for i := 0; i < sav.NumField(); i++ {
-		currentValue = sav.Field(i)
-		currentStructField = sat.Field(i)
-		if !currentValue.IsNil() {
-			if currentStructField.Name == "baseSessionArguments" {
-				// inherited/nested struct
-				nestedStruct = reflect.Indirect(currentValue)
-				for j = 0; j < nestedStruct.NumField(); j++ {
-					currentNestedValue = nestedStruct.Field(j)
-					currentNestedStructField = nestedStruct.Type().Field(j)
-					if !currentNestedValue.IsNil() {
-						JSONKeyName := currentNestedStructField.Tag.Get("json")
-						if JSONKeyName != "-" {
-							cleanPayload[JSONKeyName] = currentNestedValue.Interface()
-						}
-					}
-				}
-			} else {
-				// Overloaded field
-				cleanPayload[currentStructField.Tag.Get("json")] = currentValue.Interface()
-			}
-		}
+	pe := newPayloadEncoder(512)
+	pe.int64("alt-speed-down", sa.AltSpeedDown)
+	pe.bool("alt-speed-enabled", sa.AltSpeedEnabled)
+	pe.int64("alt-speed-time-begin", sa.AltSpeedTimeBegin)
+	pe.int64("alt-speed-time-day", sa.AltSpeedTimeDay)
+	pe.bool("alt-speed-time-enabled", sa.AltSpeedTimeEnabled)
+	pe.int64("alt-speed-time-end", sa.AltSpeedTimeEnd)
+	pe.int64("alt-speed-up", sa.AltSpeedUp)
+	pe.bool("blocklist-enabled", sa.BlocklistEnabled)
+	pe.int64("blocklist-size", sa.BlocklistSize)
+	pe.string("blocklist-url", sa.BlocklistURL)
+	pe.int64("cache-size-mb", sa.CacheSizeMB)
+	pe.string("config-dir", sa.ConfigDir)
+	pe.joinedStrings("default-trackers", sa.DefaultTrackers)
+	pe.bool("dht-enabled", sa.DHTEnabled)
+	pe.string("download-dir", sa.DownloadDir)
+	pe.bool("download-queue-enabled", sa.DownloadQueueEnabled)
+	pe.int64("download-queue-size", sa.DownloadQueueSize)
+	if sa.Encryption != nil {
+		encryption := string(*sa.Encryption)
+		pe.string("encryption", &encryption)
+	}
+	pe.bool("idle-seeding-limit-enabled", sa.IdleSeedingLimitEnabled)
+	pe.int64("idle-seeding-limit", sa.IdleSeedingLimit)
+	pe.bool("incomplete-dir-enabled", sa.IncompleteDirEnabled)
+	pe.string("incomplete-dir", sa.IncompleteDir)
+	pe.bool("lpd-enabled", sa.LPDEnabled)
+	pe.int64("peer-limit-global", sa.PeerLimitGlobal)
+	pe.int64("peer-limit-per-torrent", sa.PeerLimitPerTorrent)
+	pe.bool("peer-port-random-on-start", sa.PeerPortRandomOnStart)
+	pe.int64("peer-port", sa.PeerPort)
+	pe.bool("pex-enabled", sa.PEXEnabled)
+	pe.bool("port-forwarding-enabled", sa.PortForwardingEnabled)
+	pe.bool("queue-stalled-enabled", sa.QueueStalledEnabled)
+	pe.int64("queue-stalled-minutes", sa.QueueStalledMinutes)
+	pe.bool("rename-partial-files", sa.RenamePartialFiles)
+	pe.string("rpc-host-whitelist", sa.RPCHostWhitelist)
+	pe.bool("rpc-host-whitelist-enabled", sa.RPCHostWhitelistEnabled)
+	pe.int64("rpc-version-minimum", sa.RPCVersionMinimum)
+	pe.string("rpc-version-semver", sa.RPCVersionSemVer)
+	pe.int64("rpc-version", sa.RPCVersion)
+	pe.string("rpc-whitelist", sa.RPCWhitelist)
+	pe.bool("rpc-whitelist-enabled", sa.RPCWhitelistEnabled)
+	pe.bool("script-torrent-added-enabled", sa.ScriptTorrentAddedEnabled)
+	pe.string("script-torrent-added-filename", sa.ScriptTorrentAddedFilename)
+	pe.bool("script-torrent-done-enabled", sa.ScriptTorrentDoneEnabled)
+	pe.string("script-torrent-done-filename", sa.ScriptTorrentDoneFilename)
+	pe.bool("script-torrent-done-seeding-enabled", sa.ScriptTorrentDoneSeedingEnabled)
+	pe.string("script-torrent-done-seeding-filename", sa.ScriptTorrentDoneSeedingFilename)
+	pe.bool("seed-queue-enabled", sa.SeedQueueEnabled)
+	pe.int64("seed-queue-size", sa.SeedQueueSize)
+	pe.float64("seedRatioLimit", sa.SeedRatioLimit)
+	pe.bool("seedRatioLimited", sa.SeedRatioLimited)
+	pe.string("session-id", sa.SessionID)
+	pe.bool("speed-limit-down-enabled", sa.SpeedLimitDownEnabled)
+	pe.int64("speed-limit-down", sa.SpeedLimitDown)
+	pe.bool("speed-limit-up-enabled", sa.SpeedLimitUpEnabled)
+	pe.int64("speed-limit-up", sa.SpeedLimitUp)
+	pe.bool("start-added-torrents", sa.StartAddedTorrents)
+	pe.bool("trash-original-torrent-files", sa.TrashOriginalTorrentFiles)
+	if sa.Units != nil {
+		pe.value("units", sa.Units)
 	}
-	// Marshall the clean payload
-	return json.Marshal(cleanPayload)
+	pe.bool("utp-enabled", sa.UTPEnabled)
+	pe.string("version", sa.Version)
+	return pe.bytes()
 }
 
 // UnmarshalJSON allows to convert timestamps to golang time.Time values.
@@ -163,6 +199,14 @@ func (sa *SessionArguments) UnmarshalJSON(data []byte) (err error) {
 	return
 }
 
+// AltSpeedDays returns the alt-speed-time-day field as a days mask, 0 if the field is nil.
+func (sa SessionArguments) AltSpeedDays() AltSpeedDay {
+	if sa.AltSpeedTimeDay == nil {
+		return 0
+	}
+	return AltSpeedDay(*sa.AltSpeedTimeDay)
+}
+
 // CacheSize returns the cache size in a handy format
 func (sa SessionArguments) CacheSize() (size cunits.Bits) {
 	if sa.CacheSizeMB != nil {
@@ -171,6 +215,43 @@ func (sa SessionArguments) CacheSize() (size cunits.Bits) {
 	return
 }
 
+// SettingsFile returns the path (on the daemon host) of the settings.json file within ConfigDir.
+// An empty string is returned if ConfigDir has not been requested.
+func (sa SessionArguments) SettingsFile() string {
+	if sa.ConfigDir == nil || *sa.ConfigDir == "" {
+		return ""
+	}
+	separator := "/"
+	if strings.Contains(*sa.ConfigDir, "\\") && !strings.Contains(*sa.ConfigDir, "/") {
+		separator = "\\"
+	}
+	return strings.TrimRight(*sa.ConfigDir, separator) + separator + "settings.json"
+}
+
+// RPCSemVer parses the rpc-version-semver field (Transmission 4+) into its major, minor and patch components.
+func (sa SessionArguments) RPCSemVer() (major, minor, patch int64, err error) {
+	if sa.RPCVersionSemVer == nil {
+		err = errors.New("rpc-version-semver is nil (not requested or not supported by the daemon)")
+		return
+	}
+	version := strings.SplitN(strings.TrimPrefix(*sa.RPCVersionSemVer, "v"), "-", 2)[0]
+	version = strings.SplitN(version, "+", 2)[0]
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		err = fmt.Errorf("'%s' is not a valid semver", *sa.RPCVersionSemVer)
+		return
+	}
+	components := make([]int64, 3)
+	for index, part := range parts {
+		if components[index], err = strconv.ParseInt(part, 10, 64); err != nil {
+			err = fmt.Errorf("'%s' is not a valid semver: %w", *sa.RPCVersionSemVer, err)
+			return
+		}
+	}
+	major, minor, patch = components[0], components[1], components[2]
+	return
+}
+
 // Units is subset of SessionArguments.
 type Units struct {
 	SpeedUnits  []string `json:"speed-units"`  // 4 strings: KB/s, MB/s, GB/s, TB/s
@@ -218,11 +299,19 @@ func (c *Client) RPCVersion(ctx context.Context) (ok bool, serverVersion int64,
 }
 
 // SessionArgumentsGetAll returns global/session values.
+// If Config.SessionCacheTTL is set, values are served from the cache until they expire: pointed values
+// are shared between callers and must not be modified. Use RefreshSession() to force a refresh.
 // https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#412-accessors
 func (c *Client) SessionArgumentsGetAll(ctx context.Context) (sessionArgs SessionArguments, err error) {
-	if err = c.rpcCall(ctx, "session-get", nil, &sessionArgs); err != nil {
+	sessionArgs, generation, cached := c.sessionCache.get()
+	if cached {
+		return
+	}
+	if err = c.rpcCall(ctx, MethodSessionGet, nil, &sessionArgs); err != nil {
 		err = fmt.Errorf("'session-get' rpc method failed: %w", err)
+		return
 	}
+	c.sessionCache.set(sessionArgs, generation)
 	return
 }
 
@@ -237,7 +326,7 @@ func (c *Client) SessionArgumentsGet(ctx context.Context, fields []string) (sess
 	if err = c.validateSessionFields(fields); err != nil {
 		return
 	}
-	if err = c.rpcCall(ctx, "session-get", sessionGetParams{Fields: fields}, &sessionArgs); err != nil {
+	if err = c.rpcCall(ctx, MethodSessionGet, sessionGetParams{Fields: fields}, &sessionArgs); err != nil {
 		err = fmt.Errorf("'session-get' rpc method failed: %w", err)
 	}
 	return
@@ -276,8 +365,132 @@ func (c *Client) SessionArgumentsSet(ctx context.Context, payload SessionArgumen
 	payload.Units = nil
 	payload.Version = nil
 	// Exec
-	if err = c.rpcCall(ctx, "session-set", payload, nil); err != nil {
+	c.sessionCache.invalidate()
+	err = c.rpcCall(ctx, MethodSessionSet, payload, nil)
+	c.sessionCache.invalidate() // drop what a session-get running during the set may have cached
+	if err != nil {
 		err = fmt.Errorf("'session-set' rpc method failed: %w", err)
 	}
 	return
 }
+
+// RPCWhitelistEntries splits the rpc-whitelist field into its entries. nil is returned if the field is nil.
+func (sa SessionArguments) RPCWhitelistEntries() []string {
+	return splitCommaList(sa.RPCWhitelist)
+}
+
+// RPCHostWhitelistEntries splits the rpc-host-whitelist field into its entries. nil is returned if the field is nil.
+func (sa SessionArguments) RPCHostWhitelistEntries() []string {
+	return splitCommaList(sa.RPCHostWhitelist)
+}
+
+func splitCommaList(list *string) (entries []string) {
+	if list == nil {
+		return
+	}
+	entries = make([]string, 0)
+	for _, entry := range strings.Split(*list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return
+}
+
+// SetRPCWhitelist sets the IP addresses accepted by the RPC server (rpc-whitelist) and enables or disables its enforcement.
+// Entries can not contain commas. Beware: a whitelist not containing the address of this client will lock it out.
+func (c *Client) SetRPCWhitelist(ctx context.Context, entries []string, enabled bool) (err error) {
+	cleaned := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if strings.Contains(entry, ",") {
+			return fmt.Errorf("whitelist entry '%s' can not contain a comma", entry)
+		}
+		if entry = strings.TrimSpace(entry); entry != "" {
+			cleaned = append(cleaned, entry)
+		}
+	}
+	whitelist := strings.Join(cleaned, ",")
+	return c.SessionArgumentsSet(ctx, SessionArguments{
+		RPCWhitelist:        &whitelist,
+		RPCWhitelistEnabled: &enabled,
+	})
+}
+
+// CopySessionSettings reads the session settings of this client daemon and applies them to the dst client daemon.
+// Read only fields (see SessionArgumentsSet) and the RPC access control fields (rpc-*) specific to each instance are not copied.
+func (c *Client) CopySessionSettings(ctx context.Context, dst *Client) (err error) {
+	if dst == nil {
+		return errors.New("destination client can not be nil")
+	}
+	settings, err := c.SessionArgumentsGetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("can't read source session settings: %w", err)
+	}
+	settings.RPCHostWhitelist = nil
+	settings.RPCHostWhitelistEnabled = nil
+	settings.RPCWhitelist = nil
+	settings.RPCWhitelistEnabled = nil
+	if err = dst.SessionArgumentsSet(ctx, settings); err != nil {
+		return fmt.Errorf("can't apply session settings to destination: %w", err)
+	}
+	return
+}
+
+// SetGlobalPeerLimit sets the maximum global number of peers (peer-limit-global).
+func (c *Client) SetGlobalPeerLimit(ctx context.Context, n int64) (err error) {
+	if n <= 0 {
+		return fmt.Errorf("global peer limit must be positive: %d", n)
+	}
+	return c.SessionArgumentsSet(ctx, SessionArguments{PeerLimitGlobal: &n})
+}
+
+const (
+	// speedUnitBytes is the number of bytes in the KB unit used by the daemon for speed limits
+	speedUnitBytes = 1000
+	// speedLimitMaxKBps is the highest speed limit considered sane (10 GB/s) by the bytes helpers
+	speedLimitMaxKBps = 10 * 1000 * 1000
+)
+
+// SetGlobalUploadLimitBytes enables and sets the global upload speed limit (speed-limit-up) from a value in bytes per second.
+// It returns an error if the converted value does not look like a sane limit, see speedLimitBytesToKBps.
+func (c *Client) SetGlobalUploadLimitBytes(ctx context.Context, bytesPerSecond int64) (err error) {
+	limit, err := speedLimitBytesToKBps(bytesPerSecond)
+	if err != nil {
+		return fmt.Errorf("invalid upload limit: %w", err)
+	}
+	enabled := true
+	return c.SessionArgumentsSet(ctx, SessionArguments{
+		SpeedLimitUp:        &limit,
+		SpeedLimitUpEnabled: &enabled,
+	})
+}
+
+// SetGlobalDownloadLimitBytes enables and sets the global download speed limit (speed-limit-down) from a value in bytes per second.
+// It returns an error if the converted value does not look like a sane limit, see speedLimitBytesToKBps.
+func (c *Client) SetGlobalDownloadLimitBytes(ctx context.Context, bytesPerSecond int64) (err error) {
+	limit, err := speedLimitBytesToKBps(bytesPerSecond)
+	if err != nil {
+		return fmt.Errorf("invalid download limit: %w", err)
+	}
+	enabled := true
+	return c.SessionArgumentsSet(ctx, SessionArguments{
+		SpeedLimitDown:        &limit,
+		SpeedLimitDownEnabled: &enabled,
+	})
+}
+
+// speedLimitBytesToKBps converts a speed in bytes per second to the KBps unit of the daemon. Values below 1 KBps
+// (except 0) are rejected as they most likely are KBps values passed by mistake, as well as values above 10 GB/s.
+func speedLimitBytesToKBps(bytesPerSecond int64) (kbps int64, err error) {
+	switch {
+	case bytesPerSecond < 0:
+		err = fmt.Errorf("speed limit can't be negative: %d B/s", bytesPerSecond)
+	case bytesPerSecond > 0 && bytesPerSecond < speedUnitBytes:
+		err = fmt.Errorf("speed limit of %d B/s is below 1 KBps: was a KBps value passed instead of bytes?", bytesPerSecond)
+	case bytesPerSecond/speedUnitBytes > speedLimitMaxKBps:
+		err = fmt.Errorf("speed limit of %d B/s is above %d KBps", bytesPerSecond, speedLimitMaxKBps)
+	default:
+		kbps = bytesPerSecond / speedUnitBytes
+	}
+	return
+}
@@ -0,0 +1,57 @@
+package transmissionrpc
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sessionCache holds the last session-get (all fields) answer for a limited time.
+type sessionCache struct {
+	ttl        time.Duration
+	value      *SessionArguments
+	fetched    time.Time
+	generation uint64 // incremented by invalidate(): a fetch started before is not cached
+	access     sync.Mutex
+}
+
+// get returns the cached values if still valid, and the generation to give back to set() once fetched otherwise.
+func (sc *sessionCache) get() (sessionArgs SessionArguments, generation uint64, ok bool) {
+	if sc.ttl <= 0 {
+		return
+	}
+	defer sc.access.Unlock()
+	sc.access.Lock()
+	generation = sc.generation
+	if sc.value == nil || time.Since(sc.fetched) > sc.ttl {
+		return
+	}
+	return *sc.value, generation, true
+}
+
+// set caches the values fetched after get() returned generation, unless the cache has been invalidated since.
+func (sc *sessionCache) set(sessionArgs SessionArguments, generation uint64) {
+	if sc.ttl <= 0 {
+		return
+	}
+	defer sc.access.Unlock()
+	sc.access.Lock()
+	if generation != sc.generation {
+		return
+	}
+	sc.value = &sessionArgs
+	sc.fetched = time.Now()
+}
+
+func (sc *sessionCache) invalidate() {
+	defer sc.access.Unlock()
+	sc.access.Lock()
+	sc.value = nil
+	sc.generation++
+}
+
+// RefreshSession invalidates the session cache (see Config.SessionCacheTTL) and fetches fresh session values.
+func (c *Client) RefreshSession(ctx context.Context) (sessionArgs SessionArguments, err error) {
+	c.sessionCache.invalidate()
+	return c.SessionArgumentsGetAll(ctx)
+}
@@ -0,0 +1,25 @@
+package transmissionrpc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionCacheDropsFetchStartedBeforeInvalidate(t *testing.T) {
+	cache := sessionCache{ttl: time.Minute}
+	_, generation, ok := cache.get()
+	if ok {
+		t.Fatal("empty cache returned values")
+	}
+	version := "4.0.0"
+	cache.invalidate() // session-set while the session-get is in flight
+	cache.set(SessionArguments{Version: &version}, generation)
+	if _, _, ok = cache.get(); ok {
+		t.Error("values fetched before the invalidation were cached")
+	}
+	_, generation, _ = cache.get()
+	cache.set(SessionArguments{Version: &version}, generation)
+	if cached, _, ok := cache.get(); !ok || cached.Version == nil || *cached.Version != version {
+		t.Errorf("got %v, %v; want the cached values", cached.Version, ok)
+	}
+}
@@ -0,0 +1,54 @@
+package transmissionrpc
+
+import (
+	"reflect"
+)
+
+/*
+	Session arguments comparison
+	Configuration drift detection between daemons
+*/
+
+// SessionFieldDiff is a session field whose value differs between two SessionArguments, see SessionDiff().
+type SessionFieldDiff struct {
+	Field string      // RPC name of the field
+	A     interface{} // value in a, nil if not set
+	B     interface{} // value in b, nil if not set
+}
+
+// SessionDiff compares a and b field by field and returns the fields which differ, in the SessionArguments order.
+// A field set in only one of them is reported as a difference: compare arguments fetched with the same fields.
+// session-id is ignored as it always differs between sessions.
+func SessionDiff(a, b SessionArguments) (diffs []SessionFieldDiff) {
+	av := reflect.ValueOf(a)
+	bv := reflect.ValueOf(b)
+	st := av.Type()
+	var field string
+	var aValue, bValue interface{}
+	for i := 0; i < st.NumField(); i++ {
+		if field = st.Field(i).Tag.Get("json"); field == "session-id" {
+			continue
+		}
+		aValue = sessionFieldValue(av.Field(i))
+		bValue = sessionFieldValue(bv.Field(i))
+		if !reflect.DeepEqual(aValue, bValue) {
+			diffs = append(diffs, SessionFieldDiff{
+				Field: field,
+				A:     aValue,
+				B:     bValue,
+			})
+		}
+	}
+	return
+}
+
+// sessionFieldValue returns the value pointed by a session field, nil if the field is not set.
+func sessionFieldValue(value reflect.Value) interface{} {
+	if value.IsNil() {
+		return nil
+	}
+	if value.Kind() == reflect.Ptr {
+		return value.Elem().Interface()
+	}
+	return value.Interface()
+}
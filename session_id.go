@@ -0,0 +1,88 @@
+package transmissionrpc
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+	Session id
+	CSRF token negotiation, shared by the concurrent calls
+    https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#231-csrf-protection
+*/
+
+// sessionID holds the session id (CSRF token) sent with each request. While it is unknown (first call, or reset after
+// a disconnection), a single call negotiates it with the daemon and the concurrent ones wait for it instead of all
+// getting a 409 answer at the same time.
+type sessionID struct {
+	id          string
+	known       bool          // id (possibly empty if the daemon does not check it) has been accepted or provided by the daemon
+	negotiation chan struct{} // closed once the negotiating call is done
+	access      sync.Mutex
+}
+
+// acquire returns the session id to send. If it is unknown and nobody is negotiating it, the caller is in charge of
+// the negotiation and must call release() with the returned channel once its request is done.
+func (sid *sessionID) acquire(ctx context.Context) (id string, negotiation chan struct{}, err error) {
+	for {
+		sid.access.Lock()
+		if sid.known {
+			id = sid.id
+			sid.access.Unlock()
+			return
+		}
+		if sid.negotiation == nil {
+			sid.negotiation = make(chan struct{})
+			negotiation = sid.negotiation
+			sid.access.Unlock()
+			return
+		}
+		pending := sid.negotiation
+		sid.access.Unlock()
+		select {
+		case <-pending:
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+	}
+}
+
+// release ends the negotiation started by acquire(), if still pending: a waiting call takes over if the id is still unknown.
+func (sid *sessionID) release(negotiation chan struct{}) {
+	defer sid.access.Unlock()
+	sid.access.Lock()
+	if negotiation != nil && sid.negotiation == negotiation {
+		sid.endNegotiation()
+	}
+}
+
+// accepted marks the sent id as valid: the daemon answered without asking for a new one.
+func (sid *sessionID) accepted(id string) {
+	defer sid.access.Unlock()
+	sid.access.Lock()
+	if !sid.known && sid.id == id {
+		sid.known = true
+		sid.endNegotiation()
+	}
+}
+
+// update sets the session id, allowing the calls waiting for its negotiation to proceed.
+// An empty id resets it, to be negotiated again by the next call. The replaced id is returned.
+func (sid *sessionID) update(newID string) (previous string) {
+	defer sid.access.Unlock()
+	sid.access.Lock()
+	previous = sid.id
+	sid.id = newID
+	if sid.known = newID != ""; sid.known {
+		sid.endNegotiation()
+	}
+	return
+}
+
+func (sid *sessionID) endNegotiation() {
+	if sid.negotiation != nil {
+		close(sid.negotiation)
+		sid.negotiation = nil
+	}
+}
@@ -0,0 +1,153 @@
+package transmissionrpc
+
+import (
+	"context"
+	"fmt"
+)
+
+/*
+	Session mutators
+	Typed session-set payload
+    https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#411-mutators
+*/
+
+// SessionSetPayload holds the session values which can be modified with SessionSet(). Only the non nil fields are sent.
+type SessionSetPayload struct {
+	AltSpeedDown                     *int64       `json:"alt-speed-down"`                       // max global download speed (KBps)
+	AltSpeedEnabled                  *bool        `json:"alt-speed-enabled"`                    // true means use the alt speeds
+	AltSpeedTimeBegin                *int64       `json:"alt-speed-time-begin"`                 // when to turn on alt speeds (units: minutes after midnight)
+	AltSpeedTimeDay                  *AltSpeedDay `json:"alt-speed-time-day"`                   // what day(s) to turn on alt speeds
+	AltSpeedTimeEnabled              *bool        `json:"alt-speed-time-enabled"`               // true means the scheduled on/off times are used
+	AltSpeedTimeEnd                  *int64       `json:"alt-speed-time-end"`                   // when to turn off alt speeds (units: same)
+	AltSpeedUp                       *int64       `json:"alt-speed-up"`                         // max global upload speed (KBps)
+	BlocklistEnabled                 *bool        `json:"blocklist-enabled"`                    // true means enabled
+	BlocklistURL                     *string      `json:"blocklist-url"`                        // location of the blocklist to use for "blocklist-update"
+	CacheSizeMB                      *int64       `json:"cache-size-mb"`                        // maximum size of the disk cache (MB)
+	DefaultTrackers                  []string     `json:"default-trackers"`                     // list of default trackers to use on public torrents
+	DHTEnabled                       *bool        `json:"dht-enabled"`                          // true means allow dht in public torrents
+	DownloadDir                      *string      `json:"download-dir"`                         // default path to download torrents
+	DownloadQueueEnabled             *bool        `json:"download-queue-enabled"`               // if true, limit how many torrents can be downloaded at once
+	DownloadQueueSize                *int64       `json:"download-queue-size"`                  // max number of torrents to download at once (see download-queue-enabled)
+	Encryption                       *Encryption  `json:"encryption"`                           // "required", "preferred", "tolerated", see Encryption type constants
+	IdleSeedingLimitEnabled          *bool        `json:"idle-seeding-limit-enabled"`           // true if the seeding inactivity limit is honored by default
+	IdleSeedingLimit                 *int64       `json:"idle-seeding-limit"`                   // torrents we're seeding will be stopped if they're idle for this long
+	IncompleteDirEnabled             *bool        `json:"incomplete-dir-enabled"`               // true means keep torrents in incomplete-dir until done
+	IncompleteDir                    *string      `json:"incomplete-dir"`                       // path for incomplete torrents, when enabled
+	LPDEnabled                       *bool        `json:"lpd-enabled"`                          // true means allow Local Peer Discovery in public torrents
+	PeerLimitGlobal                  *int64       `json:"peer-limit-global"`                    // maximum global number of peers
+	PeerLimitPerTorrent              *int64       `json:"peer-limit-per-torrent"`               // maximum number of peers per torrent
+	PeerPortRandomOnStart            *bool        `json:"peer-port-random-on-start"`            // true means pick a random peer port on launch
+	PeerPort                         *int64       `json:"peer-port"`                            // port number
+	PEXEnabled                       *bool        `json:"pex-enabled"`                          // true means allow pex in public torrents
+	PortForwardingEnabled            *bool        `json:"port-forwarding-enabled"`              // true means enabled
+	QueueStalledEnabled              *bool        `json:"queue-stalled-enabled"`                // whether or not to consider idle torrents as stalled
+	QueueStalledMinutes              *int64       `json:"queue-stalled-minutes"`                // torrents that are idle for N minuets aren't counted toward seed-queue-size or download-queue-size
+	RenamePartialFiles               *bool        `json:"rename-partial-files"`                 // true means append ".part" to incomplete files
+	RPCHostWhitelist                 *string      `json:"rpc-host-whitelist"`                   // comma-separated list of the host names accepted by the RPC server (not exposed by every daemon)
+	RPCHostWhitelistEnabled          *bool        `json:"rpc-host-whitelist-enabled"`           // true means the RPC host whitelist is enforced (not exposed by every daemon)
+	RPCWhitelist                     *string      `json:"rpc-whitelist"`                        // comma-separated list of the IP addresses (wildcards allowed) accepted by the RPC server (not exposed by every daemon)
+	RPCWhitelistEnabled              *bool        `json:"rpc-whitelist-enabled"`                // true means the RPC whitelist is enforced (not exposed by every daemon)
+	ScriptTorrentAddedEnabled        *bool        `json:"script-torrent-added-enabled"`         // whether or not to call the added script
+	ScriptTorrentAddedFilename       *string      `json:"script-torrent-added-filename"`        //filename of the script to run
+	ScriptTorrentDoneEnabled         *bool        `json:"script-torrent-done-enabled"`          // whether or not to call the "done" script
+	ScriptTorrentDoneFilename        *string      `json:"script-torrent-done-filename"`         // filename of the script to run
+	ScriptTorrentDoneSeedingEnabled  *bool        `json:"script-torrent-done-seeding-enabled"`  // whether or not to call the seeding-done script
+	ScriptTorrentDoneSeedingFilename *string      `json:"script-torrent-done-seeding-filename"` // filename of the script to run
+	SeedQueueEnabled                 *bool        `json:"seed-queue-enabled"`                   // if true, limit how many torrents can be uploaded at once
+	SeedQueueSize                    *int64       `json:"seed-queue-size"`                      // max number of torrents to uploaded at once (see seed-queue-enabled)
+	SeedRatioLimit                   *float64     `json:"seedRatioLimit"`                       // the default seed ratio for torrents to use
+	SeedRatioLimited                 *bool        `json:"seedRatioLimited"`                     // true if seedRatioLimit is honored by default
+	SpeedLimitDownEnabled            *bool        `json:"speed-limit-down-enabled"`             // true means enabled
+	SpeedLimitDown                   *int64       `json:"speed-limit-down"`                     // max global download speed (KBps)
+	SpeedLimitUpEnabled              *bool        `json:"speed-limit-up-enabled"`               // true means enabled
+	SpeedLimitUp                     *int64       `json:"speed-limit-up"`                       // max global upload speed (KBps)
+	StartAddedTorrents               *bool        `json:"start-added-torrents"`                 // true means added torrents will be started right away
+	TrashOriginalTorrentFiles        *bool        `json:"trash-original-torrent-files"`         // true means the .torrent file of added torrents will be deleted
+	UTPEnabled                       *bool        `json:"utp-enabled"`                          // true means allow utp
+}
+
+// SessionArguments converts the payload into the equivalent SessionArguments.
+func (ssp SessionSetPayload) SessionArguments() (sessionArgs SessionArguments) {
+	sessionArgs = SessionArguments{
+		AltSpeedDown:                     ssp.AltSpeedDown,
+		AltSpeedEnabled:                  ssp.AltSpeedEnabled,
+		AltSpeedTimeBegin:                ssp.AltSpeedTimeBegin,
+		AltSpeedTimeEnabled:              ssp.AltSpeedTimeEnabled,
+		AltSpeedTimeEnd:                  ssp.AltSpeedTimeEnd,
+		AltSpeedUp:                       ssp.AltSpeedUp,
+		BlocklistEnabled:                 ssp.BlocklistEnabled,
+		BlocklistURL:                     ssp.BlocklistURL,
+		CacheSizeMB:                      ssp.CacheSizeMB,
+		DefaultTrackers:                  ssp.DefaultTrackers,
+		DHTEnabled:                       ssp.DHTEnabled,
+		DownloadDir:                      ssp.DownloadDir,
+		DownloadQueueEnabled:             ssp.DownloadQueueEnabled,
+		DownloadQueueSize:                ssp.DownloadQueueSize,
+		Encryption:                       ssp.Encryption,
+		IdleSeedingLimitEnabled:          ssp.IdleSeedingLimitEnabled,
+		IdleSeedingLimit:                 ssp.IdleSeedingLimit,
+		IncompleteDirEnabled:             ssp.IncompleteDirEnabled,
+		IncompleteDir:                    ssp.IncompleteDir,
+		LPDEnabled:                       ssp.LPDEnabled,
+		PeerLimitGlobal:                  ssp.PeerLimitGlobal,
+		PeerLimitPerTorrent:              ssp.PeerLimitPerTorrent,
+		PeerPortRandomOnStart:            ssp.PeerPortRandomOnStart,
+		PeerPort:                         ssp.PeerPort,
+		PEXEnabled:                       ssp.PEXEnabled,
+		PortForwardingEnabled:            ssp.PortForwardingEnabled,
+		QueueStalledEnabled:              ssp.QueueStalledEnabled,
+		QueueStalledMinutes:              ssp.QueueStalledMinutes,
+		RenamePartialFiles:               ssp.RenamePartialFiles,
+		RPCHostWhitelist:                 ssp.RPCHostWhitelist,
+		RPCHostWhitelistEnabled:          ssp.RPCHostWhitelistEnabled,
+		RPCWhitelist:                     ssp.RPCWhitelist,
+		RPCWhitelistEnabled:              ssp.RPCWhitelistEnabled,
+		ScriptTorrentAddedEnabled:        ssp.ScriptTorrentAddedEnabled,
+		ScriptTorrentAddedFilename:       ssp.ScriptTorrentAddedFilename,
+		ScriptTorrentDoneEnabled:         ssp.ScriptTorrentDoneEnabled,
+		ScriptTorrentDoneFilename:        ssp.ScriptTorrentDoneFilename,
+		ScriptTorrentDoneSeedingEnabled:  ssp.ScriptTorrentDoneSeedingEnabled,
+		ScriptTorrentDoneSeedingFilename: ssp.ScriptTorrentDoneSeedingFilename,
+		SeedQueueEnabled:                 ssp.SeedQueueEnabled,
+		SeedQueueSize:                    ssp.SeedQueueSize,
+		SeedRatioLimit:                   ssp.SeedRatioLimit,
+		SeedRatioLimited:                 ssp.SeedRatioLimited,
+		SpeedLimitDownEnabled:            ssp.SpeedLimitDownEnabled,
+		SpeedLimitDown:                   ssp.SpeedLimitDown,
+		SpeedLimitUpEnabled:              ssp.SpeedLimitUpEnabled,
+		SpeedLimitUp:                     ssp.SpeedLimitUp,
+		StartAddedTorrents:               ssp.StartAddedTorrents,
+		TrashOriginalTorrentFiles:        ssp.TrashOriginalTorrentFiles,
+		UTPEnabled:                       ssp.UTPEnabled,
+	}
+	if ssp.AltSpeedTimeDay != nil {
+		days := int64(*ssp.AltSpeedTimeDay)
+		sessionArgs.AltSpeedTimeDay = &days
+	}
+	return
+}
+
+// MarshalJSON allows to marshall into JSON only the non nil fields, see SessionArguments.MarshalJSON().
+func (ssp SessionSetPayload) MarshalJSON() (data []byte, err error) {
+	return ssp.SessionArguments().MarshalJSON()
+}
+
+// SessionGet returns the session values for the given fields, or all of them if no field is given
+// (same as SessionArgumentsGet() and SessionArgumentsGetAll()).
+func (c *Client) SessionGet(ctx context.Context, fields ...string) (sessionArgs SessionArguments, err error) {
+	if len(fields) == 0 {
+		return c.SessionArgumentsGetAll(ctx)
+	}
+	return c.SessionArgumentsGet(ctx, fields)
+}
+
+// SessionSet modifies the session values set in payload. The encryption mode and the alt speed days are validated first.
+func (c *Client) SessionSet(ctx context.Context, payload SessionSetPayload) (err error) {
+	if payload.Encryption != nil && !payload.Encryption.IsValid() {
+		return fmt.Errorf("invalid encryption mode '%s'", *payload.Encryption)
+	}
+	if payload.AltSpeedTimeDay != nil && *payload.AltSpeedTimeDay&^AltSpeedEveryday != 0 {
+		return fmt.Errorf("invalid alt speed days mask %d", *payload.AltSpeedTimeDay)
+	}
+	return c.SessionArgumentsSet(ctx, payload.SessionArguments())
+}
@@ -13,7 +13,7 @@ import (
 // SessionClose tells the transmission session to shut down.
 func (c *Client) SessionClose(ctx context.Context) (err error) {
 	// Send request
-	if err = c.rpcCall(ctx, "session-close", nil, nil); err != nil {
+	if err = c.rpcCall(ctx, MethodSessionClose, nil, nil); err != nil {
 		err = fmt.Errorf("'session-close' rpc method failed: %w", err)
 	}
 	return
@@ -14,7 +14,7 @@ import (
 
 // SessionStats returns all (current/cumulative) statistics.
 func (c *Client) SessionStats(ctx context.Context) (stats SessionStats, err error) {
-	if err = c.rpcCall(ctx, "session-stats", nil, &stats); err != nil {
+	if err = c.rpcCall(ctx, MethodSessionStats, nil, &stats); err != nil {
 		err = fmt.Errorf("'session-stats' rpc method failed: %w", err)
 	}
 	return
@@ -49,3 +49,43 @@ func (cs *SessionStatsDetails) GetDownloaded() (downloaded cunits.Bits) {
 func (cs *SessionStatsDetails) GetUploaded() (uploaded cunits.Bits) {
 	return cunits.ImportInByte(float64(cs.UploadedBytes))
 }
+
+const (
+	// RatioNotAvailable is returned as a ratio when nothing has been downloaded nor uploaded (TR_RATIO_NA)
+	RatioNotAvailable float64 = -1
+	// RatioInfinite is returned as a ratio when data has been uploaded but nothing downloaded (TR_RATIO_INF)
+	RatioInfinite float64 = -2
+)
+
+// Ratio returns the uploaded/downloaded ratio. If nothing was downloaded, RatioNotAvailable or RatioInfinite is returned.
+func (cs *SessionStatsDetails) Ratio() float64 {
+	if cs.DownloadedBytes == 0 {
+		if cs.UploadedBytes == 0 {
+			return RatioNotAvailable
+		}
+		return RatioInfinite
+	}
+	return float64(cs.UploadedBytes) / float64(cs.DownloadedBytes)
+}
+
+// OverallRatio returns the cumulative (all sessions) uploaded/downloaded ratio of the daemon.
+// If nothing was downloaded, RatioNotAvailable or RatioInfinite is returned.
+func (c *Client) OverallRatio(ctx context.Context) (ratio float64, err error) {
+	stats, err := c.SessionStats(ctx)
+	if err != nil {
+		return
+	}
+	ratio = stats.CumulativeStats.Ratio()
+	return
+}
+
+// CurrentSessionRatio returns the uploaded/downloaded ratio of the current daemon session.
+// If nothing was downloaded, RatioNotAvailable or RatioInfinite is returned.
+func (c *Client) CurrentSessionRatio(ctx context.Context) (ratio float64, err error) {
+	stats, err := c.SessionStats(ctx)
+	if err != nil {
+		return
+	}
+	ratio = stats.CurrentStats.Ratio()
+	return
+}
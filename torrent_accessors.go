@@ -7,9 +7,12 @@ package transmissionrpc
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/hekmon/cunits/v2"
@@ -45,6 +48,9 @@ func (c *Client) TorrentGet(ctx context.Context, fields []string, ids []int64) (
 	if err = c.validateTorrentFields(fields); err != nil {
 		return
 	}
+	if fields, err = c.gateTorrentGet(ctx, fields); err != nil {
+		return
+	}
 	return c.torrentGet(ctx, fields, ids)
 }
 
@@ -53,6 +59,9 @@ func (c *Client) TorrentGetHashes(ctx context.Context, fields []string, hashes [
 	if err = c.validateTorrentFields(fields); err != nil {
 		return
 	}
+	if fields, err = c.gateTorrentGet(ctx, fields); err != nil {
+		return
+	}
 	return c.torrentGetHash(ctx, fields, hashes)
 }
 
@@ -77,8 +86,16 @@ func (c *Client) validateTorrentFields(fields []string) (err error) {
 }
 
 func (c *Client) torrentGet(ctx context.Context, fields []string, ids []int64) (torrents []Torrent, err error) {
+	if batch := batchFromContext(ctx, c); batch != nil {
+		return batch.torrentGet(ctx, fields, ids)
+	}
+	return c.fetchTorrents(ctx, fields, ids)
+}
+
+// fetchTorrents always issues a torrent-get, even within a BatchContext: polling helpers must use it.
+func (c *Client) fetchTorrents(ctx context.Context, fields []string, ids []int64) (torrents []Torrent, err error) {
 	var result torrentGetResults
-	if err = c.rpcCall(ctx, "torrent-get", &torrentGetParams{
+	if err = c.rpcCall(ctx, MethodTorrentGet, &torrentGetParams{
 		Fields: fields,
 		IDs:    ids,
 	}, &result); err != nil {
@@ -86,12 +103,13 @@ func (c *Client) torrentGet(ctx context.Context, fields []string, ids []int64) (
 		return
 	}
 	torrents = result.Torrents
+	c.pruneVerifyRunStates(torrents)
 	return
 }
 
 func (c *Client) torrentGetHash(ctx context.Context, fields []string, hashes []string) (torrents []Torrent, err error) {
 	var result torrentGetResults
-	if err = c.rpcCall(ctx, "torrent-get", &torrentGetHashParams{
+	if err = c.rpcCall(ctx, MethodTorrentGet, &torrentGetHashParams{
 		Fields: fields,
 		Hashes: hashes,
 	}, &result); err != nil {
@@ -131,9 +149,9 @@ type Torrent struct {
 	DoneDate                *time.Time        `json:"doneDate"`
 	DownloadDir             *string           `json:"downloadDir"`
 	DownloadedEver          *int64            `json:"downloadedEver"`
-	DownloadLimit           *int64            `json:"downloadLimit"`
+	DownloadLimit           *int64            `json:"downloadLimit"` // KBps
 	DownloadLimited         *bool             `json:"downloadLimited"`
-	EditDate                *time.Time        `json:"editDate"`
+	EditDate                *time.Time        `json:"editDate"` // last time the torrent was modified, useful to detect concurrent edits
 	Error                   *int64            `json:"error"`
 	ErrorString             *string           `json:"errorString"`
 	ETA                     *int64            `json:"eta"`
@@ -150,8 +168,8 @@ type Torrent struct {
 	IsFinished              *bool             `json:"isFinished"`
 	IsPrivate               *bool             `json:"isPrivate"`
 	IsStalled               *bool             `json:"isStalled"`
-	Labels                  []string          `json:"labels"` // RPC v16
-	LeftUntilDone           *int64            `json:"leftUntilDone"`
+	Labels                  []string          `json:"labels"`        // RPC v16
+	LeftUntilDone           *int64            `json:"leftUntilDone"` // bytes
 	MagnetLink              *string           `json:"magnetLink"`
 	ManualAnnounceTime      *int64            `json:"manualAnnounceTime"`
 	MaxConnectedPeers       *int64            `json:"maxConnectedPeers"`
@@ -163,24 +181,24 @@ type Torrent struct {
 	PeersFrom               *TorrentPeersFrom `json:"peersFrom"`
 	PeersGettingFromUs      *int64            `json:"peersGettingFromUs"`
 	PeersSendingToUs        *int64            `json:"peersSendingToUs"`
-	PercentComplete         *float64          `json:"percentComplete"` // RPC v17
-	PercentDone             *float64          `json:"percentDone"`
+	PercentComplete         *float64          `json:"percentComplete"` // RPC v17: progress of the whole torrent
+	PercentDone             *float64          `json:"percentDone"`     // progress of the wanted files only
 	Pieces                  *string           `json:"pieces"`
 	PieceCount              *int64            `json:"pieceCount"`
-	PieceSize               *cunits.Bits      `json:"PieceSize"`
+	PieceSize               *cunits.Bits      `json:"pieceSize"`
 	Priorities              []int64           `json:"priorities"`
 	PrimaryMimeType         *string           `json:"primary-mime-type"` // RPC v17
 	QueuePosition           *int64            `json:"queuePosition"`
 	RateDownload            *int64            `json:"rateDownload"` // B/s
 	RateUpload              *int64            `json:"rateUpload"`   // B/s
 	RecheckProgress         *float64          `json:"recheckProgress"`
-	TimeDownloading         *time.Duration    `json:"secondsDownloading"`
-	TimeSeeding             *time.Duration    `json:"secondsSeeding"`
+	TimeDownloading         *time.Duration    `json:"secondsDownloading"` // cumulated, survives daemon restarts
+	TimeSeeding             *time.Duration    `json:"secondsSeeding"`     // cumulated, survives daemon restarts
 	SeedIdleLimit           *time.Duration    `json:"seedIdleLimit"`
 	SeedIdleMode            *int64            `json:"seedIdleMode"`
 	SeedRatioLimit          *float64          `json:"seedRatioLimit"`
 	SeedRatioMode           *SeedRatioMode    `json:"seedRatioMode"`
-	SizeWhenDone            *cunits.Bits      `json:"sizeWhenDone"`
+	SizeWhenDone            *cunits.Bits      `json:"sizeWhenDone"` // size of the wanted files only
 	StartDate               *time.Time        `json:"startDate"`
 	Status                  *TorrentStatus    `json:"status"`
 	Trackers                []Tracker         `json:"trackers"`
@@ -189,7 +207,7 @@ type Torrent struct {
 	TotalSize               *cunits.Bits      `json:"totalSize"`
 	TorrentFile             *string           `json:"torrentFile"`
 	UploadedEver            *int64            `json:"uploadedEver"`
-	UploadLimit             *int64            `json:"uploadLimit"`
+	UploadLimit             *int64            `json:"uploadLimit"` // KBps
 	UploadLimited           *bool             `json:"uploadLimited"`
 	UploadRatio             *float64          `json:"uploadRatio"`
 	Wanted                  []bool            `json:"wanted"`
@@ -213,6 +231,183 @@ func (t *Torrent) ConvertUploadSpeed() (speed cunits.Bits) {
 	return
 }
 
+// IsComplete returns true if all the wanted files have been downloaded (percentDone is 1).
+// Unwanted files are not taken into account, see IsFullyDownloaded for the whole torrent.
+func (t *Torrent) IsComplete() bool {
+	return t.PercentDone != nil && *t.PercentDone >= 1
+}
+
+// IsFullyDownloaded returns true if the whole torrent has been downloaded (percentComplete is 1),
+// including the files which are not wanted.
+func (t *Torrent) IsFullyDownloaded() bool {
+	return t.PercentComplete != nil && *t.PercentComplete >= 1
+}
+
+// ETAAtRate returns the time needed to download the remaining wanted data (leftUntilDone) at the given rate.
+// ok is false if leftUntilDone has not been requested or if the rate is not positive.
+func (t *Torrent) ETAAtRate(bytesPerSec int64) (eta time.Duration, ok bool) {
+	if t.LeftUntilDone == nil || bytesPerSec <= 0 {
+		return
+	}
+	seconds := float64(*t.LeftUntilDone) / float64(bytesPerSec)
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// EffectiveDownloadCap returns the torrent download limit (KBps) if it is honored (downloadLimited).
+// ok is false if the torrent is not limited or if the downloadLimit/downloadLimited fields were not requested.
+// Session wide limits are not taken into account.
+func (t *Torrent) EffectiveDownloadCap() (limit int64, ok bool) {
+	if t.DownloadLimited == nil || !*t.DownloadLimited || t.DownloadLimit == nil {
+		return
+	}
+	return *t.DownloadLimit, true
+}
+
+// EffectiveUploadCap returns the torrent upload limit (KBps) if it is honored (uploadLimited).
+// ok is false if the torrent is not limited or if the uploadLimit/uploadLimited fields were not requested.
+// Session wide limits are not taken into account.
+func (t *Torrent) EffectiveUploadCap() (limit int64, ok bool) {
+	if t.UploadLimited == nil || !*t.UploadLimited || t.UploadLimit == nil {
+		return
+	}
+	return *t.UploadLimit, true
+}
+
+// VerificationProgress returns the fraction (between 0 and 1) of the wanted data verified so far (haveValid relative
+// to sizeWhenDone) while the torrent is being checked. ok is false if the torrent is not currently being checked
+// or if one of the status, haveValid and sizeWhenDone fields has not been requested.
+func (t *Torrent) VerificationProgress() (progress float64, ok bool) {
+	if t.Status == nil || *t.Status != TorrentStatusCheck || t.HaveValid == nil || t.SizeWhenDone == nil {
+		return
+	}
+	total := t.SizeWhenDone.Byte()
+	if total <= 0 {
+		return
+	}
+	if progress = float64(*t.HaveValid) / total; progress > 1 {
+		progress = 1
+	}
+	ok = true
+	return
+}
+
+// Uptime returns how long the torrent has been running since it was last started.
+// valid is false if the startDate field was not requested, if the torrent was never started
+// or if it is currently stopped (status field, when requested).
+func (t *Torrent) Uptime() (uptime time.Duration, valid bool) {
+	return t.UptimeAt(time.Now())
+}
+
+// UptimeAt does the same as Uptime but computes the uptime against the provided time instead of now.
+func (t *Torrent) UptimeAt(now time.Time) (uptime time.Duration, valid bool) {
+	if t.StartDate == nil || t.StartDate.Unix() <= 0 {
+		return
+	}
+	if t.Status != nil && *t.Status == TorrentStatusStopped {
+		return
+	}
+	if uptime = now.Sub(*t.StartDate); uptime < 0 {
+		uptime = 0
+		return
+	}
+	valid = true
+	return
+}
+
+// CompletedPieces returns the number of pieces the torrent has, counted from the pieces bitfield
+// (base64 encoded, first piece being the most significant bit of the first byte). If pieceCount was
+// requested, bits after the last piece are ignored. ok is false if pieces was not requested or is invalid.
+func (t *Torrent) CompletedPieces() (completed int, ok bool) {
+	if t.Pieces == nil {
+		return
+	}
+	bitfield, err := base64.StdEncoding.DecodeString(*t.Pieces)
+	if err != nil {
+		return
+	}
+	maxPieces := len(bitfield) * 8
+	if t.PieceCount != nil && *t.PieceCount < int64(maxPieces) {
+		maxPieces = int(*t.PieceCount)
+	}
+	for index := 0; index < maxPieces; index++ {
+		if bitfield[index/8]&(0x80>>(index%8)) != 0 {
+			completed++
+		}
+	}
+	ok = true
+	return
+}
+
+// ExpectedPieceCount returns the number of pieces computed from totalSize and pieceSize (the last piece
+// being possibly smaller). It should match the pieceCount field. ok is false if one of the fields is missing.
+func (t *Torrent) ExpectedPieceCount() (count int64, ok bool) {
+	if t.TotalSize == nil || t.PieceSize == nil {
+		return
+	}
+	pieceSize := int64(t.PieceSize.Byte())
+	if pieceSize <= 0 {
+		return
+	}
+	totalSize := int64(t.TotalSize.Byte())
+	count = (totalSize + pieceSize - 1) / pieceSize
+	ok = true
+	return
+}
+
+// TrackerTiers returns the announce URLs of the torrent grouped by tier, in the same format TrackerListFromTiers() expects.
+// It is decoded from the trackerList field when requested (RPC v17+) or rebuilt from the trackers field tiers otherwise.
+// nil is returned if none of these fields were requested.
+func (t *Torrent) TrackerTiers() (tiers [][]string) {
+	if t.TrackerList != nil {
+		tiers = make([][]string, 0)
+		var tier []string
+		for _, line := range strings.Split(*t.TrackerList, "\n") {
+			if line = strings.TrimSpace(line); line == "" {
+				if len(tier) > 0 {
+					tiers = append(tiers, tier)
+					tier = nil
+				}
+				continue
+			}
+			tier = append(tier, line)
+		}
+		if len(tier) > 0 {
+			tiers = append(tiers, tier)
+		}
+		return
+	}
+	if t.Trackers == nil {
+		return
+	}
+	trackers := make([]Tracker, len(t.Trackers))
+	copy(trackers, t.Trackers)
+	sort.SliceStable(trackers, func(i, j int) bool { return trackers[i].Tier < trackers[j].Tier })
+	tiers = make([][]string, 0)
+	for index, tracker := range trackers {
+		if index == 0 || tracker.Tier != trackers[index-1].Tier {
+			tiers = append(tiers, nil)
+		}
+		tiers[len(tiers)-1] = append(tiers[len(tiers)-1], tracker.Announce)
+	}
+	return
+}
+
+// TrackerListFromTiers flattens tiers (as returned by Torrent.TrackerTiers()) into a TorrentSetPayload TrackerList,
+// using an empty line between tiers.
+func TrackerListFromTiers(tiers [][]string) (trackerList []string) {
+	trackerList = make([]string, 0)
+	for _, tier := range tiers {
+		if len(tier) == 0 {
+			continue
+		}
+		if len(trackerList) > 0 {
+			trackerList = append(trackerList, "")
+		}
+		trackerList = append(trackerList, tier...)
+	}
+	return
+}
+
 // UnmarshalJSON allows to convert timestamps to golang time.Time values.
 func (t *Torrent) UnmarshalJSON(data []byte) (err error) {
 	// Shadow real type for regular unmarshalling
@@ -311,10 +506,14 @@ func (t Torrent) MarshalJSON() (data []byte, err error) {
 		AddedDate          *int64  `json:"addedDate"`
 		DateCreated        *int64  `json:"dateCreated"`
 		DoneDate           *int64  `json:"doneDate"`
+		EditDate           *int64  `json:"editDate"`
+		PieceSize          *int64  `json:"pieceSize"`
 		SecondsDownloading *int64  `json:"secondsDownloading"`
 		SecondsSeeding     *int64  `json:"secondsSeeding"`
 		SeedIdleLimit      *int64  `json:"seedIdleLimit"`
+		SizeWhenDone       *int64  `json:"sizeWhenDone"`
 		StartDate          *int64  `json:"startDate"`
+		TotalSize          *int64  `json:"totalSize"`
 		Wanted             []int64 `json:"wanted"` // boolean in number form
 		*RawTorrent
 	}{
@@ -337,6 +536,10 @@ func (t Torrent) MarshalJSON() (data []byte, err error) {
 		dd := t.DoneDate.Unix()
 		tmp.DoneDate = &dd
 	}
+	if t.EditDate != nil {
+		ed := t.EditDate.Unix()
+		tmp.EditDate = &ed
+	}
 	if t.TimeDownloading != nil {
 		sd := int64(*t.TimeDownloading / time.Second)
 		tmp.SecondsDownloading = &sd
@@ -353,6 +556,19 @@ func (t Torrent) MarshalJSON() (data []byte, err error) {
 		st := t.StartDate.Unix()
 		tmp.StartDate = &st
 	}
+	// Bytes
+	if t.PieceSize != nil {
+		ps := int64(t.PieceSize.Byte())
+		tmp.PieceSize = &ps
+	}
+	if t.SizeWhenDone != nil {
+		swd := int64(t.SizeWhenDone.Byte())
+		tmp.SizeWhenDone = &swd
+	}
+	if t.TotalSize != nil {
+		ts := int64(t.TotalSize.Byte())
+		tmp.TotalSize = &ts
+	}
 	// Boolean as number
 	if t.Wanted != nil {
 		tmp.Wanted = make([]int64, len(t.Wanted))
@@ -410,6 +626,39 @@ func (p *Peer) ConvertUploadSpeed() (speed cunits.Bits) {
 	return cunits.ImportInByte(float64(p.RateToPeer))
 }
 
+// PeerSortKey defines the peer value used to sort peers, see Torrent.TopPeers().
+type PeerSortKey int
+
+const (
+	// PeerSortByDownloadRate sorts peers by download speed from them (rateToClient)
+	PeerSortByDownloadRate PeerSortKey = iota
+	// PeerSortByUploadRate sorts peers by upload speed to them (rateToPeer)
+	PeerSortByUploadRate
+	// PeerSortByProgress sorts peers by their own download progress
+	PeerSortByProgress
+)
+
+// TopPeers returns the n peers with the highest value for the given key (fastest or most advanced first).
+// The peers field must have been requested. n <= 0 returns all the peers, sorted.
+func (t *Torrent) TopPeers(n int, by PeerSortKey) (peers []Peer) {
+	peers = make([]Peer, len(t.Peers))
+	copy(peers, t.Peers)
+	var less func(i, j int) bool
+	switch by {
+	case PeerSortByUploadRate:
+		less = func(i, j int) bool { return peers[i].RateToPeer > peers[j].RateToPeer }
+	case PeerSortByProgress:
+		less = func(i, j int) bool { return peers[i].Progress > peers[j].Progress }
+	default:
+		less = func(i, j int) bool { return peers[i].RateToClient > peers[j].RateToClient }
+	}
+	sort.SliceStable(peers, less)
+	if n > 0 && n < len(peers) {
+		peers = peers[:n]
+	}
+	return
+}
+
 // TorrentPeersFrom represents the peers statistics of a torrent.
 type TorrentPeersFrom struct {
 	FromCache    int64 `json:"fromCache"`
@@ -421,6 +670,35 @@ type TorrentPeersFrom struct {
 	FromTracker  int64 `json:"fromTracker"`
 }
 
+// Total returns the number of connected peers, all sources included.
+func (tpf *TorrentPeersFrom) Total() int64 {
+	return tpf.FromCache + tpf.FromDHT + tpf.FromIncoming + tpf.FromLPD + tpf.FromLTEP + tpf.FromPEX + tpf.FromTracker
+}
+
+// DominantSource returns the source ("tracker", "dht", "pex", "incoming", "lpd", "ltep" or "cache") the most
+// connected peers come from, and their count. Ties are resolved in that order. Source is empty if there is no peer.
+// Many peers from DHT or PEX while none comes from the trackers usually indicates trackers trouble.
+func (tpf *TorrentPeersFrom) DominantSource() (source string, count int64) {
+	for _, candidate := range []struct {
+		name  string
+		count int64
+	}{
+		{"tracker", tpf.FromTracker},
+		{"dht", tpf.FromDHT},
+		{"pex", tpf.FromPEX},
+		{"incoming", tpf.FromIncoming},
+		{"lpd", tpf.FromLPD},
+		{"ltep", tpf.FromLTEP},
+		{"cache", tpf.FromCache},
+	} {
+		if candidate.count > count {
+			source = candidate.name
+			count = candidate.count
+		}
+	}
+	return
+}
+
 // SeedRatioMode represents a torrent current seeding mode
 type SeedRatioMode int64
 
@@ -460,6 +738,16 @@ func (srm SeedRatioMode) GoString() string {
 	}
 }
 
+// Seed idle modes of a torrent (seedIdleMode field)
+const (
+	// SeedIdleModeGlobal represents the use of the global idle limit for a torrent
+	SeedIdleModeGlobal int64 = 0
+	// SeedIdleModeCustom represents the use of a custom idle limit for a torrent
+	SeedIdleModeCustom int64 = 1
+	// SeedIdleModeNoLimit represents the absence of idle limit for a torrent
+	SeedIdleModeNoLimit int64 = 2
+)
+
 // TorrentStatus binds torrent status to a status code
 type TorrentStatus int64
 
@@ -2,7 +2,6 @@ package transmissionrpc
 
 import (
 	"context"
-	"fmt"
 )
 
 /*
@@ -10,144 +9,128 @@ import (
     https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#31-torrent-action-requests
 */
 
-type torrentActionIDsParam struct {
-	IDs []int64 `json:"ids,omitempty"`
-}
-
-type torrentActionHashesParam struct {
-	IDs []string `json:"ids,omitempty"`
+// idsOrAll keeps the historical semantic of the ids variants: an empty list selects all the torrents.
+func idsOrAll(ids []int64) TorrentSelector {
+	if len(ids) == 0 {
+		return All()
+	}
+	return IDs(ids...)
 }
 
-type torrentActionRecentlyActiveParam struct {
-	IDs string `json:"ids"`
+// hashesOrAll keeps the historical semantic of the hashes variants: an empty list selects all the torrents.
+func hashesOrAll(hashes []string) TorrentSelector {
+	if len(hashes) == 0 {
+		return All()
+	}
+	return Hashes(hashes...)
 }
 
 // TorrentStartIDs starts torrent(s) which id is in the provided slice.
 // Can be one, can be several, can be all (if slice is empty or nil).
 func (c *Client) TorrentStartIDs(ctx context.Context, ids []int64) (err error) {
-	if err = c.rpcCall(ctx, "torrent-start", &torrentActionIDsParam{IDs: ids}, nil); err != nil {
-		err = fmt.Errorf("'torrent-start' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentStart, idsOrAll(ids))
 }
 
 // TorrentStartHashes starts torrent(s) which hash is in the provided slice.
 // Can be one, can be several, can be all (if slice is empty or nil).
 func (c *Client) TorrentStartHashes(ctx context.Context, hashes []string) (err error) {
-	if err = c.rpcCall(ctx, "torrent-start", &torrentActionHashesParam{IDs: hashes}, nil); err != nil {
-		err = fmt.Errorf("'torrent-start' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentStart, hashesOrAll(hashes))
 }
 
 // TorrentStartRecentlyActive starts torrent(s) which have been recently active.
 func (c *Client) TorrentStartRecentlyActive(ctx context.Context) (err error) {
-	if err = c.rpcCall(ctx, "torrent-start", &torrentActionRecentlyActiveParam{IDs: "recently-active"}, nil); err != nil {
-		err = fmt.Errorf("'torrent-start' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentStart, RecentlyActive())
+}
+
+// TorrentStartAll starts all the torrents.
+func (c *Client) TorrentStartAll(ctx context.Context) (err error) {
+	return c.torrentActionSelector(ctx, MethodTorrentStart, All())
 }
 
 // TorrentStartNowIDs starts (now) torrent(s) which id is in the provided slice.
 // Can be one, can be several, can be all (if slice is empty or nil).
 func (c *Client) TorrentStartNowIDs(ctx context.Context, ids []int64) (err error) {
-	if err = c.rpcCall(ctx, "torrent-start-now", &torrentActionIDsParam{IDs: ids}, nil); err != nil {
-		err = fmt.Errorf("'torrent-start-now' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentStartNow, idsOrAll(ids))
 }
 
 // TorrentStartNowHashes starts (now) torrent(s) which hash is in the provided slice.
 // Can be one, can be several, can be all (if slice is empty or nil).
 func (c *Client) TorrentStartNowHashes(ctx context.Context, hashes []string) (err error) {
-	if err = c.rpcCall(ctx, "torrent-start-now", &torrentActionHashesParam{IDs: hashes}, nil); err != nil {
-		err = fmt.Errorf("'torrent-start-now' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentStartNow, hashesOrAll(hashes))
 }
 
 // TorrentStartNowRecentlyActive starts (now) torrent(s) which have been recently active.
 func (c *Client) TorrentStartNowRecentlyActive(ctx context.Context) (err error) {
-	if err = c.rpcCall(ctx, "torrent-start-now", &torrentActionRecentlyActiveParam{IDs: "recently-active"}, nil); err != nil {
-		err = fmt.Errorf("'torrent-start-now' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentStartNow, RecentlyActive())
+}
+
+// TorrentStartNowAll starts (now) all the torrents.
+func (c *Client) TorrentStartNowAll(ctx context.Context) (err error) {
+	return c.torrentActionSelector(ctx, MethodTorrentStartNow, All())
 }
 
 // TorrentStopIDs stops torrent(s) which id is in the provided slice.
 // Can be one, can be several, can be all (if slice is empty or nil).
 func (c *Client) TorrentStopIDs(ctx context.Context, ids []int64) (err error) {
-	if err = c.rpcCall(ctx, "torrent-stop", &torrentActionIDsParam{IDs: ids}, nil); err != nil {
-		err = fmt.Errorf("'torrent-stop' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentStop, idsOrAll(ids))
 }
 
 // TorrentStopHashes stops torrent(s) which hash is in the provided slice.
 // Can be one, can be several, can be all (if slice is empty or nil).
 func (c *Client) TorrentStopHashes(ctx context.Context, hashes []string) (err error) {
-	if err = c.rpcCall(ctx, "torrent-stop", &torrentActionHashesParam{IDs: hashes}, nil); err != nil {
-		err = fmt.Errorf("'torrent-stop' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentStop, hashesOrAll(hashes))
 }
 
 // TorrentStopRecentlyActive stops torrent(s) which have been recently active.
 func (c *Client) TorrentStopRecentlyActive(ctx context.Context) (err error) {
-	if err = c.rpcCall(ctx, "torrent-stop", &torrentActionRecentlyActiveParam{IDs: "recently-active"}, nil); err != nil {
-		err = fmt.Errorf("'torrent-stop' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentStop, RecentlyActive())
+}
+
+// TorrentStopAll stops all the torrents.
+func (c *Client) TorrentStopAll(ctx context.Context) (err error) {
+	return c.torrentActionSelector(ctx, MethodTorrentStop, All())
 }
 
 // TorrentVerifyIDs verifys torrent(s) which id is in the provided slice.
 // Can be one, can be several, can be all (if slice is empty or nil).
 func (c *Client) TorrentVerifyIDs(ctx context.Context, ids []int64) (err error) {
-	if err = c.rpcCall(ctx, "torrent-verify", &torrentActionIDsParam{IDs: ids}, nil); err != nil {
-		err = fmt.Errorf("'torrent-verify' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentVerify, idsOrAll(ids))
 }
 
 // TorrentVerifyHashes verifys torrent(s) which hash is in the provided slice.
 // Can be one, can be several, can be all (if slice is empty or nil).
 func (c *Client) TorrentVerifyHashes(ctx context.Context, hashes []string) (err error) {
-	if err = c.rpcCall(ctx, "torrent-verify", &torrentActionHashesParam{IDs: hashes}, nil); err != nil {
-		err = fmt.Errorf("'torrent-verify' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentVerify, hashesOrAll(hashes))
 }
 
 // TorrentVerifyRecentlyActive verifys torrent(s) which have been recently active.
 func (c *Client) TorrentVerifyRecentlyActive(ctx context.Context) (err error) {
-	if err = c.rpcCall(ctx, "torrent-verify", &torrentActionRecentlyActiveParam{IDs: "recently-active"}, nil); err != nil {
-		err = fmt.Errorf("'torrent-verify' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentVerify, RecentlyActive())
+}
+
+// TorrentVerifyAll verifys all the torrents.
+func (c *Client) TorrentVerifyAll(ctx context.Context) (err error) {
+	return c.torrentActionSelector(ctx, MethodTorrentVerify, All())
 }
 
 // TorrentReannounceIDs reannounces torrent(s) which id is in the provided slice.
 // Can be one, can be several, can be all (if slice is empty or nil).
 func (c *Client) TorrentReannounceIDs(ctx context.Context, ids []int64) (err error) {
-	if err = c.rpcCall(ctx, "torrent-reannounce", &torrentActionIDsParam{IDs: ids}, nil); err != nil {
-		err = fmt.Errorf("'torrent-reannounce' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentReannounce, idsOrAll(ids))
 }
 
 // TorrentReannounceHashes reannounces torrent(s) which hash is in the provided slice.
 // Can be one, can be several, can be all (if slice is empty or nil).
 func (c *Client) TorrentReannounceHashes(ctx context.Context, hashes []string) (err error) {
-	if err = c.rpcCall(ctx, "torrent-reannounce", &torrentActionHashesParam{IDs: hashes}, nil); err != nil {
-		err = fmt.Errorf("'torrent-reannounce' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentReannounce, hashesOrAll(hashes))
 }
 
 // TorrentReannounceRecentlyActive reannounces torrent(s) which have been recently active.
 func (c *Client) TorrentReannounceRecentlyActive(ctx context.Context) (err error) {
-	if err = c.rpcCall(ctx, "torrent-reannounce", &torrentActionRecentlyActiveParam{IDs: "recently-active"}, nil); err != nil {
-		err = fmt.Errorf("'torrent-reannounce' rpc method failed: %w", err)
-	}
-	return
+	return c.torrentActionSelector(ctx, MethodTorrentReannounce, RecentlyActive())
+}
+
+// TorrentReannounceAll reannounces all the torrents.
+func (c *Client) TorrentReannounceAll(ctx context.Context) (err error) {
+	return c.torrentActionSelector(ctx, MethodTorrentReannounce, All())
 }
@@ -4,12 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
-	"reflect"
+	"time"
 )
 
 /*
@@ -61,30 +61,325 @@ func (c *Client) TorrentAddFile(ctx context.Context, filepath string) (torrent T
 }
 
 // TorrentAdd allows to send an Add payload. If successful (torrent added or duplicate) torrent
-// return value will only have HashString, ID and Name fields set up.
+// return value will only have HashString, ID and Name fields set up. As torrent-add does not support
+// bandwidth groups nor seed goals, if payload Group or seed limits are set they are applied with a torrent-set once added.
+// The client AddDefaults (see Config.AddDefaults) are merged into the payload.
 func (c *Client) TorrentAdd(ctx context.Context, payload TorrentAddPayload) (torrent Torrent, err error) {
+	result, err := c.torrentAdd(ctx, payload)
+	torrent = result.Torrent
+	return
+}
+
+func (c *Client) torrentAdd(ctx context.Context, payload TorrentAddPayload) (result TorrentAddResult, err error) {
 	// Validate
 	if payload.Filename == nil && payload.MetaInfo == nil {
 		err = errors.New("fields Filename and MetaInfo can't be both nil")
 		return
 	}
+	return c.sendTorrentAdd(ctx, c.applyAddDefaults(payload))
+}
+
+// sendTorrentAdd adds the torrent of payload as is (without the client AddDefaults).
+func (c *Client) sendTorrentAdd(ctx context.Context, payload TorrentAddPayload) (result TorrentAddResult, err error) {
+	if err = c.gateTorrentAdd(ctx, &payload); err != nil {
+		return
+	}
 	// Send payload
-	var result torrentAddAnswer
-	if err = c.rpcCall(ctx, "torrent-add", payload, &result); err != nil {
+	var answer torrentAddAnswer
+	if err = c.rpcCall(ctx, MethodTorrentAdd, payload, &answer); err != nil {
 		err = fmt.Errorf("'torrent-add' rpc method failed: %w", err)
 		return
 	}
 	// Extract results
-	if result.TorrentAdded != nil {
-		torrent = *result.TorrentAdded
-	} else if result.TorrentDuplicate != nil {
-		torrent = *result.TorrentDuplicate
-	} else {
-		err = errors.New("RPC call went fine but neither 'torrent-added' nor 'torrent-duplicate' result payload were found")
+	if result, err = answer.result(); err != nil {
+		return
+	}
+	if result.Duplicate && c.settings.RejectDuplicateTorrents {
+		err = fmt.Errorf("%w: '%s' (id %d)", ErrDuplicateTorrent, deref(result.Torrent.Name), deref(result.Torrent.ID))
+		return
+	}
+	err = c.applyAddSettings(ctx, result.Torrent, payload, !result.Duplicate)
+	return
+}
+
+// TorrentAddMagnet adds a torrent from a magnet link. The link is validated and its info hash extracted
+// (see TorrentAddResult.InfoHash) before sending the request.
+func (c *Client) TorrentAddMagnet(ctx context.Context, magnet string) (result TorrentAddResult, err error) {
+	hash, err := InfoHashFromMagnet(magnet)
+	if err != nil {
+		err = fmt.Errorf("invalid magnet link: %w", err)
+		return
+	}
+	if result, err = c.torrentAdd(ctx, TorrentAddPayload{Filename: &magnet}); err != nil {
+		return
+	}
+	result.InfoHash = hash
+	return
+}
+
+// TorrentAddURL adds a torrent from the URL of its .torrent file, fetched by the daemon.
+// The info hash can not be known before the daemon fetched the file: InfoHash is left empty.
+func (c *Client) TorrentAddURL(ctx context.Context, torrentURL string) (result TorrentAddResult, err error) {
+	parsed, err := url.Parse(torrentURL)
+	if err != nil {
+		err = fmt.Errorf("invalid torrent URL: %w", err)
+		return
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		err = fmt.Errorf("unsupported torrent URL scheme '%s': must be http or https", parsed.Scheme)
+		return
+	}
+	return c.torrentAdd(ctx, TorrentAddPayload{Filename: &torrentURL})
+}
+
+// TorrentAddFileResult adds a local .torrent file (it handles the base64 encoding) like TorrentAddFile but also
+// reports if the torrent was a duplicate and its info hash, computed locally before sending the request.
+func (c *Client) TorrentAddFileResult(ctx context.Context, filepath string) (result TorrentAddResult, err error) {
+	raw, err := os.ReadFile(filepath)
+	if err != nil {
+		err = fmt.Errorf("can't read '%s': %w", filepath, err)
+		return
+	}
+	hash, err := InfoHashFromTorrent(raw)
+	if err != nil {
+		err = fmt.Errorf("'%s' is not a valid .torrent file: %w", filepath, err)
+		return
+	}
+	b64 := base64.StdEncoding.EncodeToString(raw)
+	if result, err = c.torrentAdd(ctx, TorrentAddPayload{MetaInfo: &b64}); err != nil {
+		return
 	}
+	result.InfoHash = hash
 	return
 }
 
+// applyAddDefaults merges the client AddDefaults (see Config.AddDefaults) into the payload.
+func (c *Client) applyAddDefaults(payload TorrentAddPayload) TorrentAddPayload {
+	defaults := c.settings.AddDefaults
+	if defaults == nil {
+		return payload
+	}
+	if payload.DownloadDir == nil && defaults.DownloadDir != "" {
+		downloadDir := defaults.DownloadDir
+		payload.DownloadDir = &downloadDir
+	}
+	if len(defaults.Labels) > 0 {
+		labels := make([]string, len(payload.Labels), len(payload.Labels)+len(defaults.Labels))
+		copy(labels, payload.Labels)
+		for _, label := range defaults.Labels {
+			if !containsString(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+		payload.Labels = labels
+	}
+	return payload
+}
+
+// applyAddSettings sets, with a single torrent-set, the settings of the add payload torrent-add does not support
+// (bandwidth group and seed goals). The seed goals of the client AddDefaults are only applied to newly added torrents.
+func (c *Client) applyAddSettings(ctx context.Context, torrent Torrent, payload TorrentAddPayload, added bool) (err error) {
+	ratio, idle := payload.SeedRatioLimit, payload.SeedIdleLimit
+	if defaults := c.settings.AddDefaults; defaults != nil && added {
+		if ratio == nil {
+			ratio = defaults.SeedRatioLimit
+		}
+		if idle == nil {
+			idle = defaults.SeedIdleLimit
+		}
+	}
+	if payload.Group == nil && ratio == nil && idle == nil {
+		return
+	}
+	if torrent.ID == nil {
+		return errors.New("can't apply the settings of the added torrent: torrent id is missing")
+	}
+	set := TorrentSetPayload{
+		IDs:   []int64{*torrent.ID},
+		Group: payload.Group,
+	}
+	if ratio != nil {
+		ratioMode := SeedRatioModeNoRatio
+		if *ratio >= 0 {
+			ratioMode = SeedRatioModeCustom
+			ratioLimit := *ratio
+			set.SeedRatioLimit = &ratioLimit
+		}
+		set.SeedRatioMode = &ratioMode
+	}
+	if idle != nil {
+		idleMode := SeedIdleModeNoLimit
+		if *idle >= 0 {
+			idleMode = SeedIdleModeCustom
+			idleLimit := *idle
+			set.SeedIdleLimit = &idleLimit
+		}
+		set.SeedIdleMode = &idleMode
+	}
+	if err = c.TorrentSet(ctx, set); err != nil {
+		err = fmt.Errorf("torrent added but can't apply its bandwidth group and seed goals: %w", err)
+	}
+	return
+}
+
+// AddDefaults are merged into every add payload sent by the client (see Config.AddDefaults).
+type AddDefaults struct {
+	// DownloadDir is used when the payload does not set one.
+	DownloadDir string `json:"download_dir,omitempty"`
+	// Labels are added to the payload labels.
+	Labels []string `json:"labels,omitempty"`
+	// SeedRatioLimit and SeedIdleLimit are set on the newly added torrents (with a torrent-set once added) when
+	// the payload does not set them. Same semantic as the payload fields.
+	SeedRatioLimit *float64       `json:"seed_ratio_limit,omitempty"`
+	SeedIdleLimit  *time.Duration `json:"seed_idle_limit,omitempty"`
+}
+
+// TorrentAddResult is returned by TorrentAddIdempotent and the TorrentAddMagnet, TorrentAddURL and TorrentAddFileResult helpers.
+type TorrentAddResult struct {
+	// Torrent will only have HashString, ID and Name fields set up.
+	Torrent Torrent
+	// Duplicate is true if the torrent was already present on the daemon instead of being newly added.
+	Duplicate bool
+	// InfoHash is the info hash computed locally before sending the request, empty if it could not be.
+	InfoHash string
+}
+
+// TorrentAddIdempotent sends an Add payload which can safely be retried. The info hash is computed
+// locally (from MetaInfo or from a magnet Filename) before sending the request: if the add fails with
+// a transient error (network or server side error), the daemon is asked if it knows the torrent and
+// an already present torrent is considered a success. In that case Duplicate will be true as there is no
+// way to know if the torrent was added by the failed request or was already there before.
+// Payloads whose info hash can not be computed locally (filename or URL) are rejected.
+func (c *Client) TorrentAddIdempotent(ctx context.Context, payload TorrentAddPayload) (result TorrentAddResult, err error) {
+	// Validate
+	if payload.Filename == nil && payload.MetaInfo == nil {
+		err = errors.New("fields Filename and MetaInfo can't be both nil")
+		return
+	}
+	hash, err := payload.InfoHash()
+	if err != nil {
+		err = fmt.Errorf("can't compute payload info hash: %w", err)
+		return
+	}
+	payload = c.applyAddDefaults(payload)
+	if err = c.gateTorrentAdd(ctx, &payload); err != nil {
+		return
+	}
+	// Send payload
+	var answer torrentAddAnswer
+	if err = c.rpcCall(ctx, MethodTorrentAdd, payload, &answer); err != nil {
+		if !isTransientError(err) {
+			err = fmt.Errorf("'torrent-add' rpc method failed: %w", err)
+			return
+		}
+		// Check if the torrent made it to the daemon regardless
+		torrents, checkErr := c.torrentGetHash(ctx, []string{"hashString", "id", "name"}, []string{hash})
+		if checkErr != nil || len(torrents) == 0 {
+			err = fmt.Errorf("'torrent-add' rpc method failed (and torrent '%s' is not present): %w", hash, err)
+			return
+		}
+		result.Torrent = torrents[0]
+		result.Duplicate = true
+		result.InfoHash = hash
+		err = c.applyAddSettings(ctx, result.Torrent, payload, false)
+		return
+	}
+	// Extract results
+	if result, err = answer.result(); err != nil {
+		return
+	}
+	result.InfoHash = hash
+	err = c.applyAddSettings(ctx, result.Torrent, payload, !result.Duplicate)
+	return
+}
+
+// TorrentReadd resets a broken torrent: it is removed (keeping its data) then added again to its download dir, with its
+// labels and bandwidth group, and verified. Stopped torrents are added back paused. The daemon deletes its copy of the
+// .torrent file (torrentFile) on removal, and refuses to add a torrent already present: the original .torrent content is
+// therefore captured first. It is metainfo (raw .torrent content) if provided, otherwise the daemon copy if readable
+// locally and matching the torrent hash (client on the daemon host). Without it the magnet link is used, the metadata
+// being retrieved again from the peers: this is refused for private torrents, whose metadata can't be fetched that way.
+// The client AddDefaults are not applied: the torrent is added back with its captured state only.
+func (c *Client) TorrentReadd(ctx context.Context, id int64, metainfo []byte) (torrent Torrent, err error) {
+	// Capture what is needed to add it back
+	previous, err := c.torrentGetOne(ctx, []string{"id", "hashString", "isPrivate", "torrentFile", "magnetLink",
+		"downloadDir", "status", "labels", "group"}, id)
+	if err != nil {
+		return
+	}
+	if previous.DownloadDir == nil || previous.HashString == nil {
+		err = fmt.Errorf("torrent %d download dir or hash is missing: can't add it back", id)
+		return
+	}
+	if metainfo != nil {
+		var hash string
+		if hash, err = InfoHashFromTorrent(metainfo); err != nil {
+			err = fmt.Errorf("invalid metainfo for torrent %d: %w", id, err)
+			return
+		}
+		if hash != *previous.HashString {
+			err = fmt.Errorf("metainfo hash '%s' does not match torrent %d hash '%s'", hash, id, *previous.HashString)
+			return
+		}
+	} else if previous.TorrentFile != nil && *previous.TorrentFile != "" {
+		metainfo = localTorrentCopy(*previous.TorrentFile, *previous.HashString)
+	}
+	payload := TorrentAddPayload{
+		DownloadDir: previous.DownloadDir,
+		Labels:      previous.Labels,
+	}
+	switch {
+	case metainfo != nil:
+		encoded := base64.StdEncoding.EncodeToString(metainfo)
+		payload.MetaInfo = &encoded
+	case deref(previous.IsPrivate):
+		err = fmt.Errorf("torrent %d is private and its metainfo is not available: it can't be added back from its magnet link", id)
+		return
+	case previous.MagnetLink == nil || *previous.MagnetLink == "":
+		err = fmt.Errorf("torrent %d magnet link is missing: can't add it back", id)
+		return
+	default:
+		payload.Filename = previous.MagnetLink
+	}
+	if previous.Group != nil && *previous.Group != "" {
+		payload.Group = previous.Group
+	}
+	if previous.Status != nil && *previous.Status == TorrentStatusStopped {
+		paused := true
+		payload.Paused = &paused
+	}
+	// Remove then add it back
+	if err = c.TorrentRemove(ctx, TorrentRemovePayload{IDs: []int64{id}}); err != nil {
+		return
+	}
+	var added TorrentAddResult
+	added, err = c.sendTorrentAdd(ctx, payload) // the captured state, not the client AddDefaults
+	torrent = added.Torrent
+	if err != nil {
+		err = fmt.Errorf("torrent %d (hash '%s') removed but can't be added back: %w", id, *previous.HashString, err)
+		return
+	}
+	if torrent.ID == nil {
+		err = errors.New("torrent added back but its id is missing: can't verify it")
+		return
+	}
+	err = c.TorrentVerifyIDs(ctx, []int64{*torrent.ID})
+	return
+}
+
+// localTorrentCopy returns the content of the daemon copy of a .torrent file if the client can read it (same host) and
+// it is the torrent of hash, nil otherwise (a remote client may find another file or nothing at that path).
+func localTorrentCopy(torrentFile, hash string) (metainfo []byte) {
+	content, err := os.ReadFile(torrentFile)
+	if err != nil {
+		return
+	}
+	if copyHash, err := InfoHashFromTorrent(content); err != nil || copyHash != hash {
+		return
+	}
+	return content
+}
+
 // TorrentAddPayload represents the data to send in order to add a torrent.
 type TorrentAddPayload struct {
 	Cookies           *string  `json:"cookies"`           // pointer to a string of one or more cookies
@@ -100,27 +395,31 @@ type TorrentAddPayload struct {
 	PriorityHigh      []int64  `json:"priority-high"`     // indices of high-priority file(s)
 	PriorityLow       []int64  `json:"priority-low"`      // indices of low-priority file(s)
 	PriorityNormal    []int64  `json:"priority-normal"`   // indices of normal-priority file(s)
+	Group             *string  `json:"-"`                 // bandwidth group to assign the torrent to, set with a torrent-set once added
+	// Seed goals, set with a torrent-set once added: negative means no limit, idle must be a whole number of minutes
+	SeedRatioLimit *float64       `json:"-"`
+	SeedIdleLimit  *time.Duration `json:"-"`
 }
 
 // MarshalJSON allows to marshall into JSON only the non nil fields.
 // It differs from 'omitempty' which also skip default values
 // (as 0 or false which can be valid here).
 func (tap TorrentAddPayload) MarshalJSON() (data []byte, err error) {
-	// Build a payload with only the non nil fields
-	tspv := reflect.ValueOf(tap)
-	tspt := tspv.Type()
-	cleanPayload := make(map[string]interface{}, tspt.NumField())
-	var currentValue reflect.Value
-	var currentStructField reflect.StructField
-	for i := 0; i < tspv.NumField(); i++ {
-		currentValue = tspv.Field(i)
-		currentStructField = tspt.Field(i)
-		if !currentValue.IsNil() {
-			cleanPayload[currentStructField.Tag.Get("json")] = currentValue.Interface()
-		}
-	}
-	// Marshall the clean payload
-	return json.Marshal(cleanPayload)
+	pe := newPayloadEncoder(128)
+	pe.string("cookies", tap.Cookies)
+	pe.string("download-dir", tap.DownloadDir)
+	pe.string("filename", tap.Filename)
+	pe.strings("labels", tap.Labels)
+	pe.string("metainfo", tap.MetaInfo)
+	pe.bool("paused", tap.Paused)
+	pe.int64("peer-limit", tap.PeerLimit)
+	pe.int64("bandwidthPriority", tap.BandwidthPriority)
+	pe.int64s("files-wanted", tap.FilesWanted)
+	pe.int64s("files-unwanted", tap.FilesUnwanted)
+	pe.int64s("priority-high", tap.PriorityHigh)
+	pe.int64s("priority-low", tap.PriorityLow)
+	pe.int64s("priority-normal", tap.PriorityNormal)
+	return pe.bytes()
 }
 
 type torrentAddAnswer struct {
@@ -128,6 +427,19 @@ type torrentAddAnswer struct {
 	TorrentDuplicate *Torrent `json:"torrent-duplicate"`
 }
 
+// result returns the added or already present torrent of the answer.
+func (answer torrentAddAnswer) result() (result TorrentAddResult, err error) {
+	if answer.TorrentAdded != nil {
+		result.Torrent = *answer.TorrentAdded
+	} else if answer.TorrentDuplicate != nil {
+		result.Torrent = *answer.TorrentDuplicate
+		result.Duplicate = true
+	} else {
+		err = errors.New("RPC call went fine but neither 'torrent-added' nor 'torrent-duplicate' result payload were found")
+	}
+	return
+}
+
 // File2Base64 returns the base64 encoding of the file provided by filename.
 // This can then be passed as MetaInfo in TorrentAddPayload.
 func File2Base64(filename string) (b64 string, err error) {
@@ -0,0 +1,105 @@
+package transmissionrpc
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+	Read batching
+	Coalesce the torrent-get calls of several helpers into a single one
+*/
+
+type batchContextKey struct{}
+
+// BatchContext coalesces the torrent-get calls made by the helpers of a client into a single shared torrent-get.
+// Fields needed by the helpers must be declared (at creation or with Require()) before the first read: the first read
+// fetches all the declared fields for all the torrents and the following reads are served from this shared result as
+// long as they only need fields already fetched (reads needing others fields trigger a new, wider, fetch).
+// Reads by hash and the polling helpers (VerifyTorrent, TorrentMoveDataWait, WaitForQueueIdle, ChangeStream, AdaptivePoller) are not batched.
+type BatchContext struct {
+	client   *Client
+	access   sync.Mutex
+	required map[string]bool
+	fetched  map[string]bool
+	torrents []Torrent
+}
+
+// NewBatchContext returns a context carrying a new BatchContext. Pass the returned context to the helpers which should share their reads.
+func (c *Client) NewBatchContext(ctx context.Context, fields ...string) (batchCtx context.Context, batch *BatchContext) {
+	batch = &BatchContext{
+		client:   c,
+		required: map[string]bool{"id": true},
+	}
+	batch.Require(fields...)
+	batchCtx = context.WithValue(ctx, batchContextKey{}, batch)
+	return
+}
+
+// Require declares fields needed by helpers which will read through the batch.
+func (b *BatchContext) Require(fields ...string) {
+	defer b.access.Unlock()
+	b.access.Lock()
+	for _, field := range fields {
+		b.required[field] = true
+	}
+}
+
+// Invalidate drops the shared result: next read will fetch fresh values.
+func (b *BatchContext) Invalidate() {
+	defer b.access.Unlock()
+	b.access.Lock()
+	b.fetched = nil
+	b.torrents = nil
+}
+
+func batchFromContext(ctx context.Context, c *Client) (batch *BatchContext) {
+	batch, _ = ctx.Value(batchContextKey{}).(*BatchContext)
+	if batch != nil && batch.client != c {
+		batch = nil
+	}
+	return
+}
+
+func (b *BatchContext) torrentGet(ctx context.Context, fields []string, ids []int64) (torrents []Torrent, err error) {
+	defer b.access.Unlock()
+	b.access.Lock()
+	// Do we need to fetch
+	missing := b.fetched == nil
+	for _, field := range fields {
+		b.required[field] = true
+		if !b.fetched[field] {
+			missing = true
+		}
+	}
+	if missing {
+		allFields := make([]string, 0, len(b.required))
+		for field := range b.required {
+			allFields = append(allFields, field)
+		}
+		if b.torrents, err = b.client.fetchTorrents(ctx, allFields, nil); err != nil {
+			b.fetched = nil
+			return
+		}
+		b.fetched = make(map[string]bool, len(allFields))
+		for _, field := range allFields {
+			b.fetched[field] = true
+		}
+	}
+	// Serve from the shared result
+	if len(ids) == 0 {
+		torrents = make([]Torrent, len(b.torrents))
+		copy(torrents, b.torrents)
+		return
+	}
+	wanted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	for _, torrent := range b.torrents {
+		if torrent.ID != nil && wanted[*torrent.ID] {
+			torrents = append(torrents, torrent)
+		}
+	}
+	return
+}
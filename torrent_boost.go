@@ -0,0 +1,85 @@
+package transmissionrpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*
+	Torrent boost
+	Temporary override of a torrent priority and limits, built on torrent-get and torrent-set
+*/
+
+const (
+	boostPollInterval   = 5 * time.Second
+	boostRestoreTimeout = 10 * time.Second
+)
+
+// TorrentBoost temporarily gives a torrent the best chances to download fast: high bandwidth priority, session and
+// torrent speed limits ignored and top of the queue. It blocks for d (or until the torrent is complete, or ctx is done)
+// then restores the original settings. The restore is done even if ctx is done, with its own short timeout.
+func (c *Client) TorrentBoost(ctx context.Context, id int64, d time.Duration) (err error) {
+	// Record the current settings
+	original, err := c.torrentGetOne(ctx, []string{
+		"id", "bandwidthPriority", "honorsSessionLimits", "downloadLimited", "uploadLimited", "queuePosition",
+	}, id)
+	if err != nil {
+		return
+	}
+	restore := TorrentSetPayload{
+		IDs:                 []int64{id},
+		BandwidthPriority:   original.BandwidthPriority,
+		HonorsSessionLimits: original.HonorsSessionLimits,
+		DownloadLimited:     original.DownloadLimited,
+		UploadLimited:       original.UploadLimited,
+		QueuePosition:       original.QueuePosition,
+	}
+	// Boost
+	priority := int64(1) // TR_PRI_HIGH
+	disabled := false
+	top := int64(0)
+	if err = c.TorrentSet(ctx, TorrentSetPayload{
+		IDs:                 []int64{id},
+		BandwidthPriority:   &priority,
+		HonorsSessionLimits: &disabled,
+		DownloadLimited:     &disabled,
+		UploadLimited:       &disabled,
+		QueuePosition:       &top,
+	}); err != nil {
+		return
+	}
+	defer func() {
+		restoreCtx, cancel := context.WithTimeout(context.Background(), boostRestoreTimeout)
+		defer cancel()
+		if restoreErr := c.TorrentSet(restoreCtx, restore); restoreErr != nil {
+			restoreErr = fmt.Errorf("can't restore torrent %d original settings: %w", id, restoreErr)
+			if err == nil {
+				err = restoreErr
+			} else {
+				err = fmt.Errorf("%w (and %v)", err, restoreErr)
+			}
+		}
+	}()
+	// Wait for the boost to end
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	ticker := time.NewTicker(boostPollInterval)
+	defer ticker.Stop()
+	var torrent Torrent
+	for {
+		select {
+		case <-timer.C:
+			return
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if torrent, err = c.torrentGetOne(ctx, []string{"id", "leftUntilDone"}, id); err != nil {
+			return
+		}
+		if torrent.LeftUntilDone != nil && *torrent.LeftUntilDone == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package transmissionrpc
+
+import (
+	"strings"
+	"unicode"
+)
+
+/*
+	Torrent display name
+	Sanitized version of the torrent name, safe for terminals, CSV and logs
+*/
+
+const displayNameEllipsis = '…'
+
+// latinCompositions maps the common combining marks to the "base letter, precomposed letter" pairs they compose.
+// This covers the Latin letters of the Latin-1 Supplement and Latin Extended-A blocks, which are the ones found
+// decomposed in the wild (macOS file names for example) in torrent names.
+var latinCompositions = map[rune]string{
+	'\u0300': "AÀaàEÈeèIÌiìOÒoòUÙuù",                             // grave accent
+	'\u0301': "AÁaáCĆcćEÉeéIÍiíLĹlĺNŃnńOÓoóRŔrŕSŚsśUÚuúYÝyýZŹzź", // acute accent
+	'\u0302': "AÂaâCĈcĉEÊeêGĜgĝHĤhĥIÎiîJĴjĵOÔoôSŜsŝUÛuûWŴwŵYŶyŷ", // circumflex accent
+	'\u0303': "AÃaãIĨiĩNÑnñOÕoõUŨuũ",                             // tilde
+	'\u0304': "AĀaāEĒeēIĪiīOŌoōUŪuū",                             // macron
+	'\u0306': "AĂaăEĔeĕGĞgğIĬiĭOŎoŏUŬuŭ",                         // breve
+	'\u0307': "CĊcċEĖeėGĠgġIİZŻzż",                               // dot above
+	'\u0308': "AÄaäEËeëIÏiïOÖoöUÜuüYŸyÿ",                         // diaeresis
+	'\u030A': "AÅaåUŮuů",                                         // ring above
+	'\u030B': "OŐoőUŰuű",                                         // double acute accent
+	'\u030C': "CČcčEĚeěLĽlľNŇnňRŘrřSŠsšTŤtťZŽzž",                 // caron
+	'\u0327': "CÇcçGĢgģKĶkķLĻlļNŅnņRŖrŗSŞsşTŢtţ",                 // cedilla
+	'\u0328': "AĄaąEĘeęIĮiįUŲuų",                                 // ogonek
+}
+
+// DisplayName returns the torrent name sanitized for display: control characters are replaced by spaces, invisible
+// formatting characters (bidirectional overrides for example) are removed, successive spaces are merged, and common
+// decomposed Latin accents are recomposed (a partial NFC normalization, without external dependency).
+// If maxLen is positive, the result is truncated to maxLen runes (ellipsis included) on a rune boundary.
+func (t *Torrent) DisplayName(maxLen int) string {
+	if t.Name == nil {
+		return ""
+	}
+	runes := make([]rune, 0, len(*t.Name))
+	for _, r := range *t.Name {
+		switch {
+		case unicode.IsControl(r) || unicode.IsSpace(r):
+			if len(runes) > 0 && runes[len(runes)-1] != ' ' {
+				runes = append(runes, ' ')
+			}
+		case unicode.Is(unicode.Cf, r):
+			// invisible formatting character: drop it
+		case len(runes) > 0 && latinCompositions[r] != "":
+			if composed, ok := composeLatin(runes[len(runes)-1], r); ok {
+				runes[len(runes)-1] = composed
+			} else {
+				runes = append(runes, r)
+			}
+		default:
+			runes = append(runes, r)
+		}
+	}
+	name := strings.TrimSpace(string(runes))
+	// Truncate if needed
+	if maxLen <= 0 {
+		return name
+	}
+	if runes = []rune(name); len(runes) <= maxLen {
+		return name
+	}
+	return strings.TrimSpace(string(runes[:maxLen-1])) + string(displayNameEllipsis)
+}
+
+func composeLatin(base, mark rune) (composed rune, ok bool) {
+	pairs := []rune(latinCompositions[mark])
+	for index := 0; index+1 < len(pairs); index += 2 {
+		if pairs[index] == base {
+			return pairs[index+1], true
+		}
+	}
+	return
+}
@@ -0,0 +1,223 @@
+package transmissionrpc
+
+import (
+	"time"
+)
+
+/*
+	Torrent DTO
+	A normalized representation of a torrent-get result
+*/
+
+// TorrentDTO is a normalized representation of a Torrent: plain values instead of pointers (zero values for the fields
+// which were not requested), sizes in bytes, durations, and wire quirks (sentinel ETAs, files and fileStats split
+// in two lists) resolved. It is meant to be exposed by downstream APIs, see Torrent.ToDTO().
+type TorrentDTO struct {
+	ID                  int64            `json:"id"`
+	HashString          string           `json:"hashString"`
+	Name                string           `json:"name"`
+	Comment             string           `json:"comment"`
+	Creator             string           `json:"creator"`
+	IsPrivate           bool             `json:"isPrivate"`
+	MagnetLink          string           `json:"magnetLink"`
+	TorrentFile         string           `json:"torrentFile"`
+	PrimaryMimeType     string           `json:"primaryMimeType"`
+	Labels              []string         `json:"labels"`
+	Group               string           `json:"group"`
+	DownloadDir         string           `json:"downloadDir"`
+	Status              TorrentStatus    `json:"status"`
+	StatusString        string           `json:"statusString"`
+	Error               int64            `json:"error"`
+	ErrorString         string           `json:"errorString"`
+	IsFinished          bool             `json:"isFinished"`
+	IsStalled           bool             `json:"isStalled"`
+	QueuePosition       int64            `json:"queuePosition"`
+	BandwidthPriority   int64            `json:"bandwidthPriority"`
+	PercentDone         float64          `json:"percentDone"`
+	PercentComplete     float64          `json:"percentComplete"`
+	MetadataComplete    float64          `json:"metadataPercentComplete"`
+	RecheckProgress     float64          `json:"recheckProgress"`
+	AddedDate           time.Time        `json:"addedDate"`
+	ActivityDate        time.Time        `json:"activityDate"`
+	DateCreated         time.Time        `json:"dateCreated"`
+	DoneDate            time.Time        `json:"doneDate"`
+	EditDate            time.Time        `json:"editDate"`
+	StartDate           time.Time        `json:"startDate"`
+	ETA                 *time.Duration   `json:"eta"`     // nil if not available or unknown
+	ETAIdle             *time.Duration   `json:"etaIdle"` // nil if not available or unknown
+	TimeDownloading     time.Duration    `json:"timeDownloading"`
+	TimeSeeding         time.Duration    `json:"timeSeeding"`
+	TotalSize           int64            `json:"totalSize"`    // bytes
+	SizeWhenDone        int64            `json:"sizeWhenDone"` // bytes
+	LeftUntilDone       int64            `json:"leftUntilDone"`
+	DesiredAvailable    int64            `json:"desiredAvailable"`
+	HaveValid           int64            `json:"haveValid"`
+	HaveUnchecked       int64            `json:"haveUnchecked"`
+	CorruptEver         int64            `json:"corruptEver"`
+	DownloadedEver      int64            `json:"downloadedEver"`
+	UploadedEver        int64            `json:"uploadedEver"`
+	UploadRatio         float64          `json:"uploadRatio"`
+	PieceCount          int64            `json:"pieceCount"`
+	PieceSize           int64            `json:"pieceSize"` // bytes
+	RateDownload        int64            `json:"rateDownload"`
+	RateUpload          int64            `json:"rateUpload"`
+	DownloadLimit       int64            `json:"downloadLimit"` // KBps
+	DownloadLimited     bool             `json:"downloadLimited"`
+	UploadLimit         int64            `json:"uploadLimit"` // KBps
+	UploadLimited       bool             `json:"uploadLimited"`
+	HonorsSessionLimits bool             `json:"honorsSessionLimits"`
+	SeedRatioMode       SeedRatioMode    `json:"seedRatioMode"`
+	SeedRatioLimit      float64          `json:"seedRatioLimit"`
+	SeedIdleMode        int64            `json:"seedIdleMode"`
+	SeedIdleLimit       time.Duration    `json:"seedIdleLimit"`
+	PeerLimit           int64            `json:"peerLimit"`
+	PeersConnected      int64            `json:"peersConnected"`
+	PeersGettingFromUs  int64            `json:"peersGettingFromUs"`
+	PeersSendingToUs    int64            `json:"peersSendingToUs"`
+	PeersFrom           TorrentPeersFrom `json:"peersFrom"`
+	Peers               []Peer           `json:"peers"`
+	WebSeeds            []string         `json:"webSeeds"`
+	WebSeedsSendingToUs int64            `json:"webSeedsSendingToUs"`
+	Files               []TorrentFileDTO `json:"files"`
+	TrackerTiers        [][]string       `json:"trackerTiers"`
+	Trackers            []TrackerStats   `json:"trackers"`
+}
+
+// TorrentFileDTO merges the files and fileStats (or wanted and priorities) information of a torrent file.
+type TorrentFileDTO struct {
+	Name           string  `json:"name"`
+	Length         int64   `json:"length"` // bytes
+	BytesCompleted int64   `json:"bytesCompleted"`
+	Progress       float64 `json:"progress"`
+	Wanted         bool    `json:"wanted"`
+	Priority       int64   `json:"priority"`
+}
+
+// ToDTO returns the normalized representation of the torrent.
+func (t *Torrent) ToDTO() (dto TorrentDTO) {
+	dto = TorrentDTO{
+		ID:                  deref(t.ID),
+		HashString:          deref(t.HashString),
+		Name:                deref(t.Name),
+		Comment:             deref(t.Comment),
+		Creator:             deref(t.Creator),
+		IsPrivate:           deref(t.IsPrivate),
+		MagnetLink:          deref(t.MagnetLink),
+		TorrentFile:         deref(t.TorrentFile),
+		PrimaryMimeType:     deref(t.PrimaryMimeType),
+		Labels:              t.Labels,
+		Group:               deref(t.Group),
+		DownloadDir:         deref(t.DownloadDir),
+		Error:               deref(t.Error),
+		ErrorString:         deref(t.ErrorString),
+		IsFinished:          deref(t.IsFinished),
+		IsStalled:           deref(t.IsStalled),
+		QueuePosition:       deref(t.QueuePosition),
+		BandwidthPriority:   deref(t.BandwidthPriority),
+		PercentDone:         deref(t.PercentDone),
+		PercentComplete:     deref(t.PercentComplete),
+		MetadataComplete:    deref(t.MetadataPercentComplete),
+		RecheckProgress:     deref(t.RecheckProgress),
+		AddedDate:           deref(t.AddedDate),
+		ActivityDate:        deref(t.ActivityDate),
+		DateCreated:         deref(t.DateCreated),
+		DoneDate:            deref(t.DoneDate),
+		EditDate:            deref(t.EditDate),
+		StartDate:           deref(t.StartDate),
+		ETA:                 etaDuration(t.ETA),
+		ETAIdle:             etaDuration(t.ETAIdle),
+		TimeDownloading:     deref(t.TimeDownloading),
+		TimeSeeding:         deref(t.TimeSeeding),
+		LeftUntilDone:       deref(t.LeftUntilDone),
+		DesiredAvailable:    deref(t.DesiredAvailable),
+		HaveValid:           deref(t.HaveValid),
+		HaveUnchecked:       deref(t.HaveUnchecked),
+		CorruptEver:         deref(t.CorruptEver),
+		DownloadedEver:      deref(t.DownloadedEver),
+		UploadedEver:        deref(t.UploadedEver),
+		UploadRatio:         deref(t.UploadRatio),
+		PieceCount:          deref(t.PieceCount),
+		RateDownload:        deref(t.RateDownload),
+		RateUpload:          deref(t.RateUpload),
+		DownloadLimit:       deref(t.DownloadLimit),
+		DownloadLimited:     deref(t.DownloadLimited),
+		UploadLimit:         deref(t.UploadLimit),
+		UploadLimited:       deref(t.UploadLimited),
+		HonorsSessionLimits: deref(t.HonorsSessionLimits),
+		SeedRatioMode:       deref(t.SeedRatioMode),
+		SeedRatioLimit:      deref(t.SeedRatioLimit),
+		SeedIdleMode:        deref(t.SeedIdleMode),
+		SeedIdleLimit:       deref(t.SeedIdleLimit),
+		PeerLimit:           deref(t.PeerLimit),
+		PeersConnected:      deref(t.PeersConnected),
+		PeersGettingFromUs:  deref(t.PeersGettingFromUs),
+		PeersSendingToUs:    deref(t.PeersSendingToUs),
+		PeersFrom:           deref(t.PeersFrom),
+		Peers:               t.Peers,
+		WebSeeds:            t.WebSeeds,
+		WebSeedsSendingToUs: deref(t.WebSeedsSendingToUs),
+		Files:               t.filesDTO(),
+		TrackerTiers:        t.TrackerTiers(),
+		Trackers:            t.TrackerStats,
+	}
+	if t.Status != nil {
+		dto.Status = *t.Status
+		dto.StatusString = t.Status.String()
+	}
+	if t.TotalSize != nil {
+		dto.TotalSize = int64(t.TotalSize.Byte())
+	}
+	if t.SizeWhenDone != nil {
+		dto.SizeWhenDone = int64(t.SizeWhenDone.Byte())
+	}
+	if t.PieceSize != nil {
+		dto.PieceSize = int64(t.PieceSize.Byte())
+	}
+	return
+}
+
+func (t *Torrent) filesDTO() (files []TorrentFileDTO) {
+	if t.Files == nil {
+		return
+	}
+	files = make([]TorrentFileDTO, len(t.Files))
+	for index, file := range t.Files {
+		files[index] = TorrentFileDTO{
+			Name:           file.Name,
+			Length:         file.Length,
+			BytesCompleted: file.BytesCompleted,
+		}
+		if file.Length > 0 {
+			files[index].Progress = float64(file.BytesCompleted) / float64(file.Length)
+		}
+		switch {
+		case index < len(t.FileStats):
+			files[index].Wanted = t.FileStats[index].Wanted
+			files[index].Priority = t.FileStats[index].Priority
+		default:
+			if index < len(t.Wanted) {
+				files[index].Wanted = t.Wanted[index]
+			}
+			if index < len(t.Priorities) {
+				files[index].Priority = t.Priorities[index]
+			}
+		}
+	}
+	return
+}
+
+// etaDuration converts an ETA in seconds, nil for the not available (-1) and unknown (-2) sentinel values.
+func etaDuration(eta *int64) (duration *time.Duration) {
+	if eta == nil || *eta < 0 {
+		return
+	}
+	d := time.Duration(*eta) * time.Second
+	return &d
+}
+
+func deref[T any](pointer *T) (value T) {
+	if pointer != nil {
+		value = *pointer
+	}
+	return
+}
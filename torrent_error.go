@@ -0,0 +1,82 @@
+package transmissionrpc
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+	Torrent error
+	Structured decoding of the torrent-get error/errorString fields
+*/
+
+// Torrent error codes (torrent-get error field)
+const (
+	TorrentErrorNone           int64 = 0 // everything is fine
+	TorrentErrorTrackerWarning int64 = 1 // the tracker returned a warning
+	TorrentErrorTrackerError   int64 = 2 // the tracker returned an error
+	TorrentErrorLocalError     int64 = 3 // local trouble, such as disk full or permissions error
+)
+
+// TorrentError is the error reported by the daemon for a torrent, see Torrent.TorrentError().
+type TorrentError struct {
+	Code    int64  // see TorrentError constants
+	Message string // errorString as is
+}
+
+// Error implements the error interface.
+func (te TorrentError) Error() string {
+	switch te.Code {
+	case TorrentErrorTrackerWarning:
+		return fmt.Sprintf("tracker warning: %s", te.Message)
+	case TorrentErrorTrackerError:
+		return fmt.Sprintf("tracker error: %s", te.Message)
+	case TorrentErrorLocalError:
+		return fmt.Sprintf("local error: %s", te.Message)
+	default:
+		return fmt.Sprintf("error %d: %s", te.Code, te.Message)
+	}
+}
+
+// torrentErrorRemediations maps (lower cased) errorString patterns to a suggested fix, checked in order.
+var torrentErrorRemediations = []struct {
+	pattern     string
+	remediation string
+}{
+	{"no data found", "the data is missing: verify the torrent, or set its location to where the data actually is"},
+	{"permission denied", "the daemon can't access the download dir: fix the files and folders permissions"},
+	{"read-only file system", "the download dir is read only: remount it read-write or set another location"},
+	{"no space left", "the disk is full: free some space or set another location"},
+	{"unregistered torrent", "the tracker does not know the torrent anymore: remove it"},
+	{"torrent not registered", "the tracker does not know the torrent anymore: remove it"},
+	{"torrent not found", "the tracker does not know the torrent anymore: remove it"},
+	{"passkey", "the tracker rejected your passkey: update the tracker URL with a valid one"},
+	{"could not connect to tracker", "the tracker is unreachable: check the network or wait for the tracker to come back"},
+	{"timed out", "the tracker is unreachable: check the network or wait for the tracker to come back"},
+}
+
+// Remediation returns a suggested fix for the error, based on common errorString patterns.
+// It returns an empty string if the message is not recognized.
+func (te TorrentError) Remediation() string {
+	message := strings.ToLower(te.Message)
+	for _, candidate := range torrentErrorRemediations {
+		if strings.Contains(message, candidate.pattern) {
+			return candidate.remediation
+		}
+	}
+	return ""
+}
+
+// TorrentError returns the error reported by the daemon for the torrent. ok is false if the torrent has no error
+// (or the error field was not fetched).
+func (t *Torrent) TorrentError() (te TorrentError, ok bool) {
+	if t.Error == nil || *t.Error == TorrentErrorNone {
+		return
+	}
+	te.Code = *t.Error
+	if t.ErrorString != nil {
+		te.Message = *t.ErrorString
+	}
+	ok = true
+	return
+}
@@ -0,0 +1,101 @@
+package transmissionrpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+/*
+	Torrent fields
+	Typed torrent-get field selection
+    https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#33-torrent-accessor-torrent-get
+*/
+
+//go:generate go run ./internal/torrentfieldsgen
+
+// TorrentField is a torrent-get field, see the TorrentField constants (generated from the Torrent struct) and the presets.
+type TorrentField string
+
+// MinRPCVersion returns the RPC version the field appeared in, 0 if it is available on every supported version.
+func (tf TorrentField) MinRPCVersion() int64 {
+	return torrentFieldsMinRPCVersion[string(tf)]
+}
+
+// TorrentFields is a list of torrent-get fields.
+type TorrentFields []TorrentField
+
+// Torrent fields presets, available on every supported RPC version
+var (
+	// FieldsBasic identifies a torrent and gives its overall state.
+	FieldsBasic = TorrentFields{
+		TorrentFieldID, TorrentFieldName, TorrentFieldHashString, TorrentFieldStatus, TorrentFieldPercentDone,
+		TorrentFieldTotalSize, TorrentFieldDownloadDir, TorrentFieldAddedDate, TorrentFieldError, TorrentFieldErrorString,
+	}
+	// FieldsTransfer describes the transfers of a torrent.
+	FieldsTransfer = TorrentFields{
+		TorrentFieldID, TorrentFieldStatus, TorrentFieldRateDownload, TorrentFieldRateUpload, TorrentFieldETA,
+		TorrentFieldPercentDone, TorrentFieldLeftUntilDone, TorrentFieldSizeWhenDone, TorrentFieldDownloadedEver,
+		TorrentFieldUploadedEver, TorrentFieldUploadRatio, TorrentFieldPeersConnected, TorrentFieldPeersGettingFromUs,
+		TorrentFieldPeersSendingToUs,
+	}
+	// FieldsFiles describes the files of a torrent and their download settings.
+	FieldsFiles = TorrentFields{
+		TorrentFieldID, TorrentFieldFiles, TorrentFieldFileStats, TorrentFieldPriorities, TorrentFieldWanted,
+	}
+	// FieldsTrackers describes the trackers of a torrent and their announces.
+	FieldsTrackers = TorrentFields{
+		TorrentFieldID, TorrentFieldTrackers, TorrentFieldTrackerStats,
+	}
+)
+
+// Strings returns the fields as the raw strings expected by TorrentGet().
+func (tfs TorrentFields) Strings() (fields []string) {
+	fields = make([]string, len(tfs))
+	for index, field := range tfs {
+		fields[index] = string(field)
+	}
+	return
+}
+
+// With returns the union of the fields and others, without duplicates (for example FieldsBasic.With(FieldsTransfer...)).
+func (tfs TorrentFields) With(others ...TorrentField) (fields TorrentFields) {
+	fields = make(TorrentFields, 0, len(tfs)+len(others))
+	seen := make(map[TorrentField]bool, len(tfs)+len(others))
+	for _, field := range append(append(TorrentFields(nil), tfs...), others...) {
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	return
+}
+
+// Validate returns an error if a field is unknown or not available with rpcVersion.
+func (tfs TorrentFields) Validate(rpcVersion int64) (err error) {
+	var unsupported []string
+	for _, field := range tfs {
+		if !containsString(validTorrentFields, string(field)) {
+			return fmt.Errorf("field '%s' is invalid", field)
+		}
+		if minVersion := field.MinRPCVersion(); rpcVersion < minVersion {
+			unsupported = append(unsupported, fmt.Sprintf("'%s' (RPC v%d)", field, minVersion))
+		}
+	}
+	if len(unsupported) > 0 {
+		err = fmt.Errorf("fields not available with RPC v%d: %s", rpcVersion, strings.Join(unsupported, ", "))
+	}
+	return
+}
+
+// TorrentGetFields is TorrentGet() with typed fields, validated against the server RPC version (see ServerRPCVersion()).
+func (c *Client) TorrentGetFields(ctx context.Context, fields TorrentFields, ids []int64) (torrents []Torrent, err error) {
+	version, err := c.ServerRPCVersion(ctx)
+	if err != nil {
+		return
+	}
+	if err = fields.Validate(version); err != nil {
+		return
+	}
+	return c.torrentGet(ctx, fields.Strings(), ids)
+}
@@ -0,0 +1,184 @@
+package transmissionrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+)
+
+/*
+	Files helpers
+	Built on torrent-get file-count/files fields and torrent-set files mutators
+*/
+
+// torrentFileCount returns the number of files of a torrent. The lightweight file-count field is used,
+// the whole files list is only fetched on daemons too old to know it (RPC < v17).
+func (c *Client) torrentFileCount(ctx context.Context, id int64) (count int64, err error) {
+	torrent, err := c.torrentGetOne(ctx, []string{"id", "file-count"}, id)
+	if err != nil {
+		return
+	}
+	if torrent.FileCount != nil {
+		return *torrent.FileCount, nil
+	}
+	if torrent, err = c.torrentGetOne(ctx, []string{"id", "files"}, id); err != nil {
+		return
+	}
+	count = int64(len(torrent.Files))
+	return
+}
+
+// TorrentSetFilePriorities sets the priority of the files of a torrent, priorities maps file indices to their
+// tr_priority_t (-1 low, 0 normal, 1 high). Indices are validated against the torrent file count before anything is set.
+func (c *Client) TorrentSetFilePriorities(ctx context.Context, id int64, priorities map[int64]int64) (err error) {
+	if len(priorities) == 0 {
+		return errors.New("there must be at least one file priority")
+	}
+	count, err := c.torrentFileCount(ctx, id)
+	if err != nil {
+		return fmt.Errorf("can't get torrent %d file count: %w", id, err)
+	}
+	payload := TorrentSetPayload{IDs: []int64{id}}
+	for index, priority := range priorities {
+		if index < 0 || index >= count {
+			return fmt.Errorf("file index %d is out of range: torrent %d has %d files", index, id, count)
+		}
+		switch priority {
+		case -1:
+			payload.PriorityLow = append(payload.PriorityLow, index)
+		case 0:
+			payload.PriorityNormal = append(payload.PriorityNormal, index)
+		case 1:
+			payload.PriorityHigh = append(payload.PriorityHigh, index)
+		default:
+			return fmt.Errorf("file %d priority %d is invalid: must be -1, 0 or 1", index, priority)
+		}
+	}
+	return c.TorrentSet(ctx, payload)
+}
+
+// TorrentSetFilesWantedByPattern marks as wanted (or unwanted) the files of a torrent whose name (path within the
+// torrent) matches pattern (see path.Match). The indices of the matched files are returned, nothing is set if none matched.
+func (c *Client) TorrentSetFilesWantedByPattern(ctx context.Context, id int64, pattern string, wanted bool) (matched []int64, err error) {
+	if _, err = path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+	}
+	torrent, err := c.torrentGetOne(ctx, []string{"id", "files"}, id)
+	if err != nil {
+		return
+	}
+	for index, file := range torrent.Files {
+		if ok, _ := path.Match(pattern, file.Name); ok {
+			matched = append(matched, int64(index))
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+	payload := TorrentSetPayload{IDs: []int64{id}}
+	if wanted {
+		payload.FilesWanted = matched
+	} else {
+		payload.FilesUnwanted = matched
+	}
+	err = c.TorrentSet(ctx, payload)
+	return
+}
+
+// TorrentPrioritizeForStreaming prepares a torrent file to be played while downloading: the file is wanted with a high
+// priority, as are the files sharing its first and last pieces (so players can read its header and footer early),
+// and the other files get a low priority (their wanted state is kept). Sequential download is enabled as well when the
+// daemon supports it (RPC v18), see FeatureSet.SequentialDownloadSupported.
+func (c *Client) TorrentPrioritizeForStreaming(ctx context.Context, id int64, fileIndex int64) (err error) {
+	torrent, err := c.torrentGetOne(ctx, []string{"id", "files", "pieceSize"}, id)
+	if err != nil {
+		return
+	}
+	if fileIndex < 0 || fileIndex >= int64(len(torrent.Files)) {
+		return fmt.Errorf("file index %d is out of range: torrent %d has %d files", fileIndex, id, len(torrent.Files))
+	}
+	if torrent.PieceSize == nil || torrent.PieceSize.Byte() <= 0 {
+		return fmt.Errorf("torrent %d piece size is unknown", id)
+	}
+	features, err := c.Features(ctx)
+	if err != nil {
+		return
+	}
+	// Find the pieces holding the beginning and the end of the file
+	pieceSize := int64(torrent.PieceSize.Byte())
+	var offset, start, end int64
+	offsets := make([]int64, len(torrent.Files))
+	for index, file := range torrent.Files {
+		offsets[index] = offset
+		if int64(index) == fileIndex {
+			start = offset
+			end = offset + file.Length
+		}
+		offset += file.Length
+	}
+	firstPiece := start / pieceSize
+	lastPiece := firstPiece
+	if end > start {
+		lastPiece = (end - 1) / pieceSize
+	}
+	// High priority for the files overlapping these pieces, low for others
+	payload := TorrentSetPayload{
+		IDs:         []int64{id},
+		FilesWanted: []int64{fileIndex},
+	}
+	var fileFirst, fileLast int64
+	for index, file := range torrent.Files {
+		if int64(index) == fileIndex {
+			payload.PriorityHigh = append(payload.PriorityHigh, fileIndex)
+			continue
+		}
+		if file.Length > 0 {
+			fileFirst = offsets[index] / pieceSize
+			fileLast = (offsets[index] + file.Length - 1) / pieceSize
+			if (fileFirst <= firstPiece && firstPiece <= fileLast) || (fileFirst <= lastPiece && lastPiece <= fileLast) {
+				payload.PriorityHigh = append(payload.PriorityHigh, int64(index))
+				continue
+			}
+		}
+		payload.PriorityLow = append(payload.PriorityLow, int64(index))
+	}
+	if features.SequentialDownloadSupported {
+		sequential := true
+		payload.SequentialDownload = &sequential
+	}
+	return c.TorrentSet(ctx, payload)
+}
+
+// TorrentDeselectSmallFiles marks as unwanted the files of a torrent smaller than maxBytes (samples, nfo, etc.)
+// and returns their indices. maxBytes must be positive. Nothing is set if no file is smaller, and an error is returned
+// if every file is (the torrent would have nothing left to download).
+func (c *Client) TorrentDeselectSmallFiles(ctx context.Context, id int64, maxBytes int64) (deselected []int64, err error) {
+	if maxBytes <= 0 {
+		err = fmt.Errorf("max bytes %d is invalid: must be positive", maxBytes)
+		return
+	}
+	torrent, err := c.torrentGetOne(ctx, []string{"id", "files"}, id)
+	if err != nil {
+		return
+	}
+	for index, file := range torrent.Files {
+		if file.Length < maxBytes {
+			deselected = append(deselected, int64(index))
+		}
+	}
+	if len(deselected) == 0 {
+		return
+	}
+	if len(deselected) == len(torrent.Files) {
+		deselected = nil
+		err = fmt.Errorf("all the %d files of torrent %d are smaller than %d bytes: refusing to deselect them all",
+			len(torrent.Files), id, maxBytes)
+		return
+	}
+	err = c.TorrentSet(ctx, TorrentSetPayload{
+		IDs:           []int64{id},
+		FilesUnwanted: deselected,
+	})
+	return
+}
@@ -0,0 +1,43 @@
+package transmissionrpc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestTorrentDeselectSmallFilesGuards(t *testing.T) {
+	var sets int
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		switch rq.Method {
+		case MethodTorrentGet:
+			writeStubAnswer(t, w, rq, map[string]interface{}{"torrents": []interface{}{map[string]interface{}{
+				"id": 1,
+				"files": []interface{}{
+					map[string]interface{}{"name": "movie.mkv", "length": 1 << 30, "bytesCompleted": 0},
+					map[string]interface{}{"name": "movie.nfo", "length": 512, "bytesCompleted": 0},
+				},
+			}}})
+		case MethodTorrentSet:
+			sets++
+			writeStubAnswer(t, w, rq, nil)
+		default:
+			t.Errorf("unexpected method '%s'", rq.Method)
+		}
+	})
+	ctx := context.Background()
+	if _, err := client.TorrentDeselectSmallFiles(ctx, 1, 0); err == nil {
+		t.Error("zero max bytes accepted")
+	}
+	if _, err := client.TorrentDeselectSmallFiles(ctx, 1, 1<<31); err == nil {
+		t.Error("deselection of every file accepted")
+	}
+	if sets != 0 {
+		t.Errorf("got %d torrent-set, want none", sets)
+	}
+	deselected, err := client.TorrentDeselectSmallFiles(ctx, 1, 1<<20)
+	if err != nil || len(deselected) != 1 || deselected[0] != 1 {
+		t.Errorf("got %v, %v; want file 1 deselected", deselected, err)
+	}
+}
@@ -0,0 +1,182 @@
+package transmissionrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+/*
+	Streamed torrent accessor
+	Built on torrent-get, requesting and decoding the torrents chunk by chunk
+	https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#33-torrent-accessor-torrent-get
+*/
+
+// TorrentIterItem is a torrent sent by TorrentGetIter(), or the error which ended the iteration.
+type TorrentIterItem struct {
+	Torrent Torrent
+	Err     error
+}
+
+// defaultTorrentIterChunk is the number of torrents requested at a time by TorrentGetIter() when chunkSize is not positive.
+const defaultTorrentIterChunk = 250
+
+// TorrentGetIter is TorrentGet() for large instances: the torrents are requested chunkSize ids at a time (250 if chunkSize
+// is not positive, the ids being fetched first if ids is empty) and sent on the returned channel chunk by chunk, instead of
+// holding all of them in memory. Each chunk is decoded incrementally and only sent once its request succeeded: the client
+// can be used from within the consumer loop. The "id" field is always requested, the fields unsupported by the daemon
+// are handled as for TorrentGet() (see Config.UnsupportedFields). The channel is closed once all the
+// torrents have been sent, after an item carrying the error if one occurred.
+// The consumer must read the channel until it is closed, or cancel ctx.
+func (c *Client) TorrentGetIter(ctx context.Context, fields []string, ids []int64, chunkSize int) (torrents <-chan TorrentIterItem, err error) {
+	if err = c.validateTorrentFields(fields); err != nil {
+		return
+	}
+	if chunkSize <= 0 {
+		chunkSize = defaultTorrentIterChunk
+	}
+	if fields, err = c.gateTorrentGet(ctx, fields); err != nil {
+		return
+	}
+	fields = withTorrentField(fields, "id")
+	output := make(chan TorrentIterItem)
+	go c.torrentGetIter(ctx, fields, ids, chunkSize, output)
+	torrents = output
+	return
+}
+
+func (c *Client) torrentGetIter(ctx context.Context, fields []string, ids []int64, chunkSize int, output chan<- TorrentIterItem) {
+	defer close(output)
+	var err error
+	defer func() {
+		if err != nil {
+			select {
+			case output <- TorrentIterItem{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	if len(ids) == 0 {
+		var all []Torrent
+		if all, err = c.fetchTorrents(ctx, []string{"id"}, nil); err != nil {
+			return
+		}
+		ids = make([]int64, 0, len(all))
+		for _, torrent := range all {
+			if torrent.ID != nil {
+				ids = append(ids, *torrent.ID)
+			}
+		}
+	}
+	var chunk []Torrent
+	for start := 0; start < len(ids); start += chunkSize {
+		end := start + chunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if chunk, err = c.streamTorrents(ctx, fields, ids[start:end]); err != nil {
+			return
+		}
+		// The request is over (its RPC slot released): the consumer can use the client
+		for _, torrent := range chunk {
+			select {
+			case output <- TorrentIterItem{Torrent: torrent}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) streamTorrents(ctx context.Context, fields []string, ids []int64) (torrents []Torrent, err error) {
+	streamer := &torrentStreamer{}
+	if err = c.rpcCall(ctx, MethodTorrentGet, &torrentGetParams{
+		Fields: fields,
+		IDs:    ids,
+	}, streamer); err != nil {
+		err = fmt.Errorf("'torrent-get' rpc method failed: %w", err)
+		return
+	}
+	torrents = streamer.torrents
+	return
+}
+
+// argumentsStreamer is implemented by the answer results decoding the answer arguments themselves, from the body stream.
+type argumentsStreamer interface {
+	streamArguments(decoder *json.Decoder) error
+}
+
+// torrentStreamer decodes the torrents of a torrent-get answer one at a time, without keeping the raw answer.
+type torrentStreamer struct {
+	torrents []Torrent
+}
+
+func (ts *torrentStreamer) streamArguments(decoder *json.Decoder) (err error) {
+	ts.torrents = ts.torrents[:0] // previous attempt of the same call
+	return decodeJSONObject(decoder, func(key string) (err error) {
+		if key != "torrents" {
+			var skipped json.RawMessage
+			return decoder.Decode(&skipped)
+		}
+		if err = expectJSONDelim(decoder, '['); err != nil {
+			return
+		}
+		for decoder.More() {
+			var torrent Torrent
+			if err = decoder.Decode(&torrent); err != nil {
+				return
+			}
+			ts.torrents = append(ts.torrents, torrent)
+		}
+		return expectJSONDelim(decoder, ']')
+	})
+}
+
+// decodeStreamedAnswer decodes an answer body, its arguments being decoded by streamer.
+func decodeStreamedAnswer(decoder *json.Decoder, answer *answerPayload, streamer argumentsStreamer) (err error) {
+	return decodeJSONObject(decoder, func(key string) error {
+		switch key {
+		case "arguments":
+			return streamer.streamArguments(decoder)
+		case "result":
+			return decoder.Decode(&answer.Result)
+		case "tag":
+			return decoder.Decode(&answer.Tag)
+		default:
+			var skipped json.RawMessage
+			return decoder.Decode(&skipped)
+		}
+	})
+}
+
+// decodeJSONObject reads a JSON object from decoder, calling value for each key: it must consume the associated value.
+func decodeJSONObject(decoder *json.Decoder, value func(key string) error) (err error) {
+	if err = expectJSONDelim(decoder, '{'); err != nil {
+		return
+	}
+	var token json.Token
+	for decoder.More() {
+		if token, err = decoder.Token(); err != nil {
+			return
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("unexpected JSON token %v instead of an object key", token)
+		}
+		if err = value(key); err != nil {
+			return
+		}
+	}
+	return expectJSONDelim(decoder, '}')
+}
+
+func expectJSONDelim(decoder *json.Decoder, delim json.Delim) (err error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected JSON token %v instead of '%v'", token, delim)
+	}
+	return
+}
@@ -0,0 +1,52 @@
+package transmissionrpc
+
+import (
+	"strconv"
+)
+
+/*
+	Torrent identifiers
+	Numeric ids and hashes accepted alike by the torrent selectors
+    https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#31-torrent-action-requests
+*/
+
+// TorrentID identifies a torrent either by its numeric id or by its SHA1 info hash, see ID() and Hash().
+// The daemon accepts both within the same request: hashes do not have to be resolved to ids first.
+type TorrentID struct {
+	id   int64
+	hash string
+}
+
+// ID identifies a torrent by its numeric id.
+func ID(id int64) TorrentID {
+	return TorrentID{id: id}
+}
+
+// Hash identifies a torrent by its SHA1 info hash.
+func Hash(hash string) TorrentID {
+	return TorrentID{hash: hash}
+}
+
+// IsHash returns true if the torrent is identified by its hash.
+func (tid TorrentID) IsHash() bool {
+	return tid.hash != ""
+}
+
+func (tid TorrentID) String() string {
+	if tid.IsHash() {
+		return tid.hash
+	}
+	return strconv.FormatInt(tid.id, 10)
+}
+
+// Torrents selects the torrents identified by ids, to be used with the selector variants of the torrent methods.
+func Torrents(ids ...TorrentID) (selector TorrentSelector) {
+	for _, tid := range ids {
+		if tid.IsHash() {
+			selector.hashes = append(selector.hashes, tid.hash)
+		} else {
+			selector.ids = append(selector.ids, tid.id)
+		}
+	}
+	return
+}
@@ -0,0 +1,98 @@
+package transmissionrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+/*
+	Labels helpers
+	Built on torrent-get and torrent-set labels field (RPC v16)
+*/
+
+// LabelRule pairs a torrent name pattern with the labels to add to the matching torrents.
+type LabelRule struct {
+	Pattern *regexp.Regexp
+	Labels  []string
+}
+
+// AutoLabel applies the rules to all the torrents: labels of every rule whose pattern matches a torrent name
+// are added to the torrent existing labels. It returns the resulting labels of each torrent which had labels
+// added. Torrents sharing the same resulting labels are updated with a single torrent-set.
+func (c *Client) AutoLabel(ctx context.Context, rules []LabelRule) (updated map[int64][]string, err error) {
+	// Validate
+	if len(rules) == 0 {
+		err = errors.New("there must be at least one rule")
+		return
+	}
+	for index, rule := range rules {
+		if rule.Pattern == nil {
+			err = fmt.Errorf("rule %d has a nil pattern", index)
+			return
+		}
+		if len(rule.Labels) == 0 {
+			err = fmt.Errorf("rule %d has no labels", index)
+			return
+		}
+		for _, label := range rule.Labels {
+			if label == "" || strings.Contains(label, ",") {
+				err = fmt.Errorf("rule %d has an invalid label '%s': labels can't be empty or contain commas", index, label)
+				return
+			}
+		}
+	}
+	// Get names and current labels
+	torrents, err := c.torrentGet(ctx, []string{"id", "name", "labels"}, nil)
+	if err != nil {
+		return
+	}
+	// Compute the new labels
+	updated = make(map[int64][]string)
+	batches := make(map[string][]int64)
+	for _, torrent := range torrents {
+		if torrent.ID == nil || torrent.Name == nil {
+			continue
+		}
+		labels := append([]string(nil), torrent.Labels...)
+		var changed bool
+		for _, rule := range rules {
+			if !rule.Pattern.MatchString(*torrent.Name) {
+				continue
+			}
+			for _, label := range rule.Labels {
+				if !containsString(labels, label) {
+					labels = append(labels, label)
+					changed = true
+				}
+			}
+		}
+		if changed {
+			updated[*torrent.ID] = labels
+			key := strings.Join(labels, ",")
+			batches[key] = append(batches[key], *torrent.ID)
+		}
+	}
+	// Apply them
+	for key, ids := range batches {
+		if err = c.TorrentSet(ctx, TorrentSetPayload{
+			IDs:    ids,
+			Labels: updated[ids[0]],
+		}); err != nil {
+			err = fmt.Errorf("can't set labels '%s': %w", key, err)
+			return
+		}
+	}
+	return
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package transmissionrpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*
+	Torrents listing
+	Sorting, filtering and pagination built on torrent-get
+*/
+
+// ListSortKey defines the value torrents are sorted by in ListTorrents().
+type ListSortKey int
+
+const (
+	// ListSortByID sorts torrents by id (default)
+	ListSortByID ListSortKey = iota
+	// ListSortByName sorts torrents by name (case insensitive)
+	ListSortByName
+	// ListSortByProgress sorts torrents by percentDone
+	ListSortByProgress
+	// ListSortByRatio sorts torrents by uploadRatio
+	ListSortByRatio
+	// ListSortByAdded sorts torrents by addedDate
+	ListSortByAdded
+	// ListSortBySize sorts torrents by totalSize
+	ListSortBySize
+)
+
+// ListOptions configures ListTorrents().
+type ListOptions struct {
+	SortBy     ListSortKey
+	Descending bool
+	// Offset of the first torrent of the page and Limit the page size (0 means no limit).
+	Offset int
+	Limit  int
+	// Label and Status (optional) filter the torrents.
+	Label  string
+	Status *TorrentStatus
+	// Fields returned for the torrents of the page, all fields if empty.
+	Fields []string
+}
+
+// ListResult is a page of torrents returned by ListTorrents().
+type ListResult struct {
+	Total    int // number of torrents matching the filters, all pages included
+	Torrents []Torrent
+}
+
+// ListTorrents returns a sorted, filtered and paginated list of torrents. Only the fields needed to sort and filter are
+// fetched for all the torrents: the requested fields are then fetched for the torrents of the page only.
+func (c *Client) ListTorrents(ctx context.Context, opts ListOptions) (result ListResult, err error) {
+	// Validate
+	if opts.Offset < 0 || opts.Limit < 0 {
+		err = fmt.Errorf("offset (%d) and limit (%d) can not be negative", opts.Offset, opts.Limit)
+		return
+	}
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = validTorrentFields
+	} else if err = c.validateTorrentFields(fields); err != nil {
+		return
+	}
+	sortField, err := opts.SortBy.field()
+	if err != nil {
+		return
+	}
+	// Fetch what is needed to sort & filter
+	selectFields := []string{"id", sortField}
+	if opts.Label != "" {
+		selectFields = append(selectFields, "labels")
+	}
+	if opts.Status != nil {
+		selectFields = append(selectFields, "status")
+	}
+	candidates, err := c.torrentGet(ctx, selectFields, nil)
+	if err != nil {
+		return
+	}
+	matching := make([]Torrent, 0, len(candidates))
+	for _, torrent := range candidates {
+		if torrent.ID == nil {
+			continue
+		}
+		if opts.Label != "" && !containsString(torrent.Labels, opts.Label) {
+			continue
+		}
+		if opts.Status != nil && (torrent.Status == nil || *torrent.Status != *opts.Status) {
+			continue
+		}
+		matching = append(matching, torrent)
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		if opts.Descending {
+			return opts.SortBy.less(matching[j], matching[i])
+		}
+		return opts.SortBy.less(matching[i], matching[j])
+	})
+	// Paginate
+	result.Total = len(matching)
+	if opts.Offset >= len(matching) {
+		result.Torrents = []Torrent{}
+		return
+	}
+	page := matching[opts.Offset:]
+	if opts.Limit > 0 && opts.Limit < len(page) {
+		page = page[:opts.Limit]
+	}
+	// Fetch the page
+	ids := make([]int64, len(page))
+	for index, torrent := range page {
+		ids[index] = *torrent.ID
+	}
+	torrents, err := c.torrentGet(ctx, withTorrentField(fields, "id"), ids)
+	if err != nil {
+		return
+	}
+	byID := make(map[int64]Torrent, len(torrents))
+	for _, torrent := range torrents {
+		if torrent.ID != nil {
+			byID[*torrent.ID] = torrent
+		}
+	}
+	result.Torrents = make([]Torrent, 0, len(ids))
+	for _, id := range ids {
+		if torrent, found := byID[id]; found { // removed in between otherwise
+			result.Torrents = append(result.Torrents, torrent)
+		}
+	}
+	return
+}
+
+func (key ListSortKey) field() (field string, err error) {
+	switch key {
+	case ListSortByID:
+		field = "id"
+	case ListSortByName:
+		field = "name"
+	case ListSortByProgress:
+		field = "percentDone"
+	case ListSortByRatio:
+		field = "uploadRatio"
+	case ListSortByAdded:
+		field = "addedDate"
+	case ListSortBySize:
+		field = "totalSize"
+	default:
+		err = fmt.Errorf("unknown sort key %d", key)
+	}
+	return
+}
+
+// less compares the sort values of 2 torrents, missing values first. Equal values are ordered by id.
+func (key ListSortKey) less(a, b Torrent) bool {
+	switch key {
+	case ListSortByName:
+		if nameA, nameB := strings.ToLower(deref(a.Name)), strings.ToLower(deref(b.Name)); nameA != nameB {
+			return nameA < nameB
+		}
+	case ListSortByProgress:
+		if deref(a.PercentDone) != deref(b.PercentDone) {
+			return deref(a.PercentDone) < deref(b.PercentDone)
+		}
+	case ListSortByRatio:
+		if deref(a.UploadRatio) != deref(b.UploadRatio) {
+			return deref(a.UploadRatio) < deref(b.UploadRatio)
+		}
+	case ListSortByAdded:
+		if dateA, dateB := deref(a.AddedDate), deref(b.AddedDate); !dateA.Equal(dateB) {
+			return dateA.Before(dateB)
+		}
+	case ListSortBySize:
+		if deref(a.TotalSize) != deref(b.TotalSize) {
+			return deref(a.TotalSize) < deref(b.TotalSize)
+		}
+	}
+	return deref(a.ID) < deref(b.ID)
+}
@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"reflect"
-	"sort"
 	"strings"
 	"time"
 )
@@ -15,124 +13,282 @@ import (
 	Torrent Mutators
     https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#32-torrent-mutator-torrent-set
 */
-// Compact replaces consecutive runs of equal elements with a single copy.
-// This is like the uniq command found on Unix.
-// Compact modifies the contents of the slice s and returns the modified slice,
-// which may have a smaller length.
-// Compact zeroes the elements between the new length and the original length.
-// todo replace to slices.Compact on version 1.21
-func compact[S ~[]E, E comparable](s S) S {
-	if len(s) < 2 {
-		return s
-	}
-	for k := 1; k < len(s); k++ {
-		if s[k] == s[k-1] {
-			s2 := s[k:]
-			for k2 := 1; k2 < len(s2); k2++ {
-				if s2[k2] != s2[k2-1] {
-					s[k] = s2[k2]
-					k++
-				}
+
+// dedupTrackerTiers removes the duplicated announce URLs within each tier of a tracker list (tiers being separated
+// by empty lines) and the superfluous empty lines. Tiers and announce URLs order is preserved.
+func dedupTrackerTiers(trackerList []string) (cleaned []string) {
+	cleaned = make([]string, 0, len(trackerList))
+	var tier map[string]bool
+	for _, announce := range trackerList {
+		if announce = strings.TrimSpace(announce); announce == "" {
+			if len(tier) > 0 {
+				cleaned = append(cleaned, "")
+				tier = nil
 			}
-			return s[:k]
+			continue
+		}
+		if tier == nil {
+			tier = make(map[string]bool)
 		}
+		if tier[announce] {
+			continue
+		}
+		tier[announce] = true
+		cleaned = append(cleaned, announce)
+	}
+	if len(cleaned) > 0 && cleaned[len(cleaned)-1] == "" {
+		cleaned = cleaned[:len(cleaned)-1]
 	}
-	return s
+	return
 }
 
-// TorrentSet apply a list of mutator(s) to a list of torrent ids.
+// TorrentSet apply a list of mutator(s) to a list of torrent ids (or to the torrents of payload Selector).
+// At least one id is required: applying mutators to all the torrents at once (All() selector) is deliberately not supported.
+// Unless Config.AllowPrivateTrackerEdit is set, a TrackerList adding new trackers to a private torrent is refused.
+// Large ids lists are sent in several calls if Config.MaxTorrentSetBatch is set.
+// A SeedIdleLimit which is not a whole number of minutes is refused unless Config.RoundSeedIdleLimit is set.
 func (c *Client) TorrentSet(ctx context.Context, payload TorrentSetPayload) (err error) {
+	return c.torrentSet(ctx, payload, nil)
+}
+
+// torrentSet is TorrentSet, replacements (new announce URL: replaced one) being let through the private torrents guard.
+func (c *Client) torrentSet(ctx context.Context, payload TorrentSetPayload, replacements map[string]string) (err error) {
 	// Validate
-	if len(payload.IDs) == 0 {
+	var selector TorrentSelector
+	switch {
+	case payload.Selector != nil && payload.IDs != nil:
+		return errors.New("IDs and Selector can not be both set")
+	case payload.Selector != nil:
+		selector = *payload.Selector
+	default:
+		selector = IDs(payload.IDs...)
+	}
+	if selector.IsEmpty() {
 		return errors.New("there must be at least one ID")
 	}
-	//fix trackers
-	sort.Strings(payload.TrackerList)
-	payload.TrackerList = compact(payload.TrackerList)
-	// Send payload
-	if err = c.rpcCall(ctx, "torrent-set", payload, nil); err != nil {
-		err = fmt.Errorf("'torrent-set' rpc method failed: %w", err)
+	if selector.IsAll() {
+		return errors.New("applying mutators to all the torrents is not supported: select them explicitly")
+	}
+	if err = payload.validateFiles(); err != nil {
+		return
+	}
+	if payload.SeedIdleLimit != nil && *payload.SeedIdleLimit%time.Minute != 0 && !c.roundSeedIdleLimit {
+		return fmt.Errorf("seed idle limit %v is not a whole number of minutes (set Config.RoundSeedIdleLimit to round it down)", *payload.SeedIdleLimit)
+	}
+	if err = c.gateTorrentSet(ctx, &payload); err != nil {
+		return
+	}
+	// Clean up trackers without altering the tiers
+	if payload.TrackerList != nil {
+		payload.TrackerList = dedupTrackerTiers(payload.TrackerList)
+	}
+	// Protect private torrents
+	if payload.TrackerList != nil && !c.allowPrivateTrackerEdit {
+		if err = c.checkPrivateTrackerEdit(ctx, selector, payload.TrackerList, replacements); err != nil {
+			return
+		}
+	}
+	// Send payload, in batches if needed (selectors are sent as is)
+	if payload.Selector != nil {
+		if err = c.rpcCall(ctx, MethodTorrentSet, payload, nil); err != nil {
+			err = fmt.Errorf("'torrent-set' rpc method failed: %w", err)
+		}
+		return
+	}
+	ids := payload.IDs
+	batchSize := len(ids)
+	if c.maxTorrentSetBatch > 0 && c.maxTorrentSetBatch < batchSize {
+		batchSize = c.maxTorrentSetBatch
+	}
+	for start := 0; start < len(ids); start += batchSize {
+		if err = ctx.Err(); err != nil {
+			return fmt.Errorf("torrent-set interrupted after %d of %d ids: %w", start, len(ids), err)
+		}
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		payload.IDs = ids[start:end]
+		if err = c.rpcCall(ctx, MethodTorrentSet, payload, nil); err != nil {
+			return fmt.Errorf("'torrent-set' rpc method failed: %w", err)
+		}
+	}
+	return
+}
+
+// validateFiles rejects negative file indices and file indices appearing in conflicting mutators
+// (both wanted and unwanted, or in more than one priority).
+func (tsp TorrentSetPayload) validateFiles() (err error) {
+	groups := []struct {
+		first, second         string
+		firstList, secondList []int64
+	}{
+		{"files-wanted", "files-unwanted", tsp.FilesWanted, tsp.FilesUnwanted},
+		{"priority-high", "priority-low", tsp.PriorityHigh, tsp.PriorityLow},
+		{"priority-high", "priority-normal", tsp.PriorityHigh, tsp.PriorityNormal},
+		{"priority-low", "priority-normal", tsp.PriorityLow, tsp.PriorityNormal},
+	}
+	for _, group := range groups {
+		if len(group.firstList) == 0 && len(group.secondList) == 0 {
+			continue
+		}
+		inFirst := make(map[int64]bool, len(group.firstList))
+		for _, index := range group.firstList {
+			if index < 0 {
+				return fmt.Errorf("file index %d in %s is negative", index, group.first)
+			}
+			inFirst[index] = true
+		}
+		for _, index := range group.secondList {
+			if index < 0 {
+				return fmt.Errorf("file index %d in %s is negative", index, group.second)
+			}
+			if inFirst[index] {
+				return fmt.Errorf("file index %d appears in both %s and %s", index, group.first, group.second)
+			}
+		}
 	}
 	return
 }
 
+// TorrentSetSeedGoal sets both the seeding ratio and idle limits of the torrents, taking care of the mode/value pairing.
+// For each limit: nil means the torrent follows the session (global) limit, a negative value removes the limit
+// and any other value is used as a custom limit for these torrents. idle must be a whole number of minutes
+// (see Config.RoundSeedIdleLimit).
+func (c *Client) TorrentSetSeedGoal(ctx context.Context, ids []int64, ratio *float64, idle *time.Duration) (err error) {
+	payload := TorrentSetPayload{IDs: ids}
+	// Ratio
+	var ratioMode SeedRatioMode
+	switch {
+	case ratio == nil:
+		ratioMode = SeedRatioModeGlobal
+	case *ratio < 0:
+		ratioMode = SeedRatioModeNoRatio
+	default:
+		ratioMode = SeedRatioModeCustom
+		ratioLimit := *ratio
+		payload.SeedRatioLimit = &ratioLimit
+	}
+	payload.SeedRatioMode = &ratioMode
+	// Idle
+	var idleMode int64
+	switch {
+	case idle == nil:
+		idleMode = SeedIdleModeGlobal
+	case *idle < 0:
+		idleMode = SeedIdleModeNoLimit
+	default:
+		idleMode = SeedIdleModeCustom
+		idleLimit := *idle
+		payload.SeedIdleLimit = &idleLimit
+	}
+	payload.SeedIdleMode = &idleMode
+	// Send
+	return c.TorrentSet(ctx, payload)
+}
+
 // TorrentSetPayload contains all the mutators appliable on one torrent.
 type TorrentSetPayload struct {
-	BandwidthPriority   *int64         `json:"bandwidthPriority"`   // this torrent's bandwidth tr_priority_t
-	DownloadLimit       *int64         `json:"downloadLimit"`       // maximum download speed (KBps)
-	DownloadLimited     *bool          `json:"downloadLimited"`     // true if "downloadLimit" is honored
-	FilesWanted         []int64        `json:"files-wanted"`        // indices of file(s) to download
-	FilesUnwanted       []int64        `json:"files-unwanted"`      // indices of file(s) to not download
-	Group               *string        `json:"group"`               // bandwidth group to add torrent to
-	HonorsSessionLimits *bool          `json:"honorsSessionLimits"` // true if session upload limits are honored
-	IDs                 []int64        `json:"ids"`                 // torrent list
-	Labels              []string       `json:"labels"`              // RPC v16: strings of user-defined labels
-	Location            *string        `json:"location"`            // new location of the torrent's content
-	PeerLimit           *int64         `json:"peer-limit"`          // maximum number of peers
-	PriorityHigh        []int64        `json:"priority-high"`       // indices of high-priority file(s)
-	PriorityLow         []int64        `json:"priority-low"`        // indices of low-priority file(s)
-	PriorityNormal      []int64        `json:"priority-normal"`     // indices of normal-priority file(s)
-	QueuePosition       *int64         `json:"queuePosition"`       // position of this torrent in its queue [0...n)
-	SeedIdleLimit       *time.Duration `json:"-"`                   // torrent-level number of minutes of seeding inactivity
-	SeedIdleMode        *int64         `json:"seedIdleMode"`        // which seeding inactivity to use
-	SeedRatioLimit      *float64       `json:"seedRatioLimit"`      // torrent-level seeding ratio
-	SeedRatioMode       *SeedRatioMode `json:"seedRatioMode"`       // which ratio mode to use
-	TrackerList         []string       `json:"-"`                   // string of announce URLs, one per line, and a blank line between tiers
-	UploadLimit         *int64         `json:"uploadLimit"`         // maximum upload speed (KBps)
-	UploadLimited       *bool          `json:"uploadLimited"`       // true if "uploadLimit" is honored
+	BandwidthPriority   *int64           `json:"bandwidthPriority"`   // this torrent's bandwidth tr_priority_t
+	DownloadLimit       *int64           `json:"downloadLimit"`       // maximum download speed (KBps)
+	DownloadLimited     *bool            `json:"downloadLimited"`     // true if "downloadLimit" is honored
+	FilesWanted         []int64          `json:"files-wanted"`        // indices of file(s) to download
+	FilesUnwanted       []int64          `json:"files-unwanted"`      // indices of file(s) to not download
+	Group               *string          `json:"group"`               // bandwidth group to add torrent to
+	HonorsSessionLimits *bool            `json:"honorsSessionLimits"` // true if session upload limits are honored
+	IDs                 []int64          `json:"ids"`                 // torrent list
+	Labels              []string         `json:"labels"`              // RPC v16: strings of user-defined labels
+	Location            *string          `json:"location"`            // new location of the torrent's content
+	PeerLimit           *int64           `json:"peer-limit"`          // maximum number of peers
+	PriorityHigh        []int64          `json:"priority-high"`       // indices of high-priority file(s)
+	PriorityLow         []int64          `json:"priority-low"`        // indices of low-priority file(s)
+	PriorityNormal      []int64          `json:"priority-normal"`     // indices of normal-priority file(s)
+	QueuePosition       *int64           `json:"queuePosition"`       // position of this torrent in its queue [0...n)
+	SeedIdleLimit       *time.Duration   `json:"-"`                   // torrent-level number of minutes of seeding inactivity
+	SeedIdleMode        *int64           `json:"seedIdleMode"`        // which seeding inactivity to use (see SeedIdleMode constants)
+	SeedRatioLimit      *float64         `json:"seedRatioLimit"`      // torrent-level seeding ratio
+	SeedRatioMode       *SeedRatioMode   `json:"seedRatioMode"`       // which ratio mode to use
+	Selector            *TorrentSelector `json:"-"`                   // alternative to IDs: hashes, recently active or all torrents
+	SequentialDownload  *bool            `json:"sequential_download"` // RPC v18: download the pieces in order
+	TrackerList         []string         `json:"-"`                   // announce URLs, an empty string between tiers (see TrackerListFromTiers)
+	UploadLimit         *int64           `json:"uploadLimit"`         // maximum upload speed (KBps)
+	UploadLimited       *bool            `json:"uploadLimited"`       // true if "uploadLimit" is honored
 }
 
 // MarshalJSON allows to marshall into JSON only the non nil fields.
 // It differs from 'omitempty' which also skip default values
 // (as 0 or false which can be valid here).
 func (tsp TorrentSetPayload) MarshalJSON() (data []byte, err error) {
-	// Build an intermediary payload with base types
+	pe := newPayloadEncoder(128)
+	pe.int64("bandwidthPriority", tsp.BandwidthPriority)
+	pe.int64("downloadLimit", tsp.DownloadLimit)
+	pe.bool("downloadLimited", tsp.DownloadLimited)
+	pe.int64s("files-wanted", tsp.FilesWanted)
+	pe.int64s("files-unwanted", tsp.FilesUnwanted)
+	pe.string("group", tsp.Group)
+	pe.bool("honorsSessionLimits", tsp.HonorsSessionLimits)
+	if tsp.Selector != nil {
+		pe.selector("ids", tsp.Selector)
+	} else {
+		pe.int64s("ids", tsp.IDs)
+	}
+	pe.strings("labels", tsp.Labels)
+	pe.string("location", tsp.Location)
+	pe.int64("peer-limit", tsp.PeerLimit)
+	pe.int64s("priority-high", tsp.PriorityHigh)
+	pe.int64s("priority-low", tsp.PriorityLow)
+	pe.int64s("priority-normal", tsp.PriorityNormal)
+	pe.int64("queuePosition", tsp.QueuePosition)
+	if tsp.SeedIdleLimit != nil {
+		sil := int64(*tsp.SeedIdleLimit / time.Minute)
+		pe.int64("seedIdleLimit", &sil)
+	}
+	pe.int64("seedIdleMode", tsp.SeedIdleMode)
+	pe.float64("seedRatioLimit", tsp.SeedRatioLimit)
+	if tsp.SeedRatioMode != nil {
+		mode := int64(*tsp.SeedRatioMode)
+		pe.int64("seedRatioMode", &mode)
+	}
+	pe.bool("sequential_download", tsp.SequentialDownload)
+	pe.joinedStrings("trackerList", tsp.TrackerList)
+	pe.int64("uploadLimit", tsp.UploadLimit)
+	pe.bool("uploadLimited", tsp.UploadLimited)
+	return pe.bytes()
+}
+
+// UnmarshalJSON allows to decode a payload produced by MarshalJSON: fields absent from data are left nil.
+func (tsp *TorrentSetPayload) UnmarshalJSON(data []byte) (err error) {
+	// Shadow real type for regular unmarshalling
 	type baseTorrentSetPayload TorrentSetPayload
 	tmp := struct {
-		SeedIdleLimit *int64  `json:"seedIdleLimit"`
-		TrackerList   *string `json:"trackerList"`
+		IDs           *TorrentSelector `json:"ids"`
+		SeedIdleLimit *int64           `json:"seedIdleLimit"`
+		TrackerList   *string          `json:"trackerList"`
 		*baseTorrentSetPayload
 	}{
-		baseTorrentSetPayload: (*baseTorrentSetPayload)(&tsp),
+		baseTorrentSetPayload: (*baseTorrentSetPayload)(tsp),
 	}
-	if tsp.SeedIdleLimit != nil {
-		sil := int64(*tsp.SeedIdleLimit / time.Minute)
-		tmp.SeedIdleLimit = &sil
-	}
-	if tsp.TrackerList != nil {
-		oneLineList := strings.Join(tsp.TrackerList, "\n")
-		tmp.TrackerList = &oneLineList
-	}
-	// Build a payload with only the non nil fields
-	tspv := reflect.ValueOf(tmp)
-	tspt := tspv.Type()
-	cleanPayload := make(map[string]interface{}, tspt.NumField())
-	var currentValue, nestedStruct, currentNestedValue reflect.Value
-	var currentStructField, currentNestedStructField reflect.StructField
-	var j int
-	for i := 0; i < tspv.NumField(); i++ {
-		currentValue = tspv.Field(i)
-		currentStructField = tspt.Field(i)
-		if !currentValue.IsNil() {
-			if currentStructField.Name == "baseTorrentSetPayload" {
-				// inherited/nested struct
-				nestedStruct = reflect.Indirect(currentValue)
-				for j = 0; j < nestedStruct.NumField(); j++ {
-					currentNestedValue = nestedStruct.Field(j)
-					currentNestedStructField = nestedStruct.Type().Field(j)
-					if !currentNestedValue.IsNil() {
-						JSONKeyName := currentNestedStructField.Tag.Get("json")
-						if JSONKeyName != "-" {
-							cleanPayload[JSONKeyName] = currentNestedValue.Interface()
-						}
-					}
-				}
-			} else {
-				// Overloaded field
-				cleanPayload[currentStructField.Tag.Get("json")] = currentValue.Interface()
+	if err = json.Unmarshal(data, &tmp); err != nil {
+		return
+	}
+	// Plain ids go to IDs, others selections to Selector
+	if tmp.IDs != nil {
+		if !tmp.IDs.recentlyActive && len(tmp.IDs.hashes) == 0 {
+			tsp.IDs = tmp.IDs.ids
+			if tsp.IDs == nil {
+				tsp.IDs = []int64{}
 			}
+		} else {
+			tsp.Selector = tmp.IDs
 		}
 	}
-	// Marshall the clean payload
-	return json.Marshal(cleanPayload)
+	// Convert back to golang types
+	if tmp.SeedIdleLimit != nil {
+		sil := time.Duration(*tmp.SeedIdleLimit) * time.Minute
+		tsp.SeedIdleLimit = &sil
+	}
+	if tmp.TrackerList != nil {
+		tsp.TrackerList = strings.Split(*tmp.TrackerList, "\n")
+	}
+	return
 }
@@ -0,0 +1,368 @@
+package transmissionrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTrackerTiersRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		tiers [][]string
+		want  [][]string
+	}{
+		{"nil", nil, [][]string{}},
+		{"single tier", [][]string{{"https://a/announce", "https://b/announce"}}, nil},
+		{"three tiers", [][]string{{"https://a/announce"}, {"https://b/announce", "https://c/announce"}, {"udp://d:80"}}, nil},
+		{"empty tiers skipped", [][]string{{}, {"https://a/announce"}, nil, {}, {"https://b/announce"}, {}},
+			[][]string{{"https://a/announce"}, {"https://b/announce"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			want := test.want
+			if want == nil {
+				want = test.tiers
+			}
+			joined := strings.Join(TrackerListFromTiers(test.tiers), "\n")
+			torrent := Torrent{TrackerList: &joined}
+			if got := torrent.TrackerTiers(); !reflect.DeepEqual(got, want) {
+				t.Errorf("got tiers %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTrackerTiersBlankSeparators(t *testing.T) {
+	list := "\n \nhttps://a/announce\n\n\n\t\nhttps://b/announce\n  https://c/announce  \n\n"
+	torrent := Torrent{TrackerList: &list}
+	want := [][]string{{"https://a/announce"}, {"https://b/announce", "https://c/announce"}}
+	if got := torrent.TrackerTiers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got tiers %q, want %q", got, want)
+	}
+}
+
+func TestDedupTrackerTiers(t *testing.T) {
+	tests := []struct {
+		name string
+		list []string
+		want []string
+	}{
+		{"empty", []string{}, []string{}},
+		{"duplicate within a tier", []string{"a", "", "b", "c", "b", "", "d"}, []string{"a", "", "b", "c", "", "d"}},
+		{"same announce in two tiers kept", []string{"a", "", "a"}, []string{"a", "", "a"}},
+		{"blank separators collapsed", []string{"", " ", "a", "", "", "\t", "b", ""}, []string{"a", "", "b"}},
+		{"order preserved", []string{"z", "y", "x"}, []string{"z", "y", "x"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := dedupTrackerTiers(test.list); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTorrentSetKeepsTrackerTiers(t *testing.T) {
+	var trackerList string
+	client := newStubClient(t, &Config{AllowPrivateTrackerEdit: true}, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		var arguments struct {
+			TrackerList string `json:"trackerList"`
+		}
+		if err := json.Unmarshal(rq.Arguments, &arguments); err != nil {
+			t.Errorf("can't decode arguments: %v", err)
+		}
+		trackerList = arguments.TrackerList
+		writeStubAnswer(t, w, rq, nil)
+	})
+	err := client.TorrentSet(context.Background(), TorrentSetPayload{
+		IDs: []int64{1},
+		TrackerList: TrackerListFromTiers([][]string{
+			{"https://a/announce"},
+			{"https://b/announce", "https://c/announce", "https://b/announce"},
+			{"https://d/announce"},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("TorrentSet() failed: %v", err)
+	}
+	if want := "https://a/announce\n\nhttps://b/announce\nhttps://c/announce\n\nhttps://d/announce"; trackerList != want {
+		t.Errorf("got trackerList %q, want %q", trackerList, want)
+	}
+}
+
+func TestTorrentSetPayloadJSONRoundTrip(t *testing.T) {
+	hashes := Hashes("0123456789abcdef0123456789abcdef01234567")
+	recentlyActive := RecentlyActive()
+	tests := []struct {
+		name    string
+		payload TorrentSetPayload
+	}{
+		{"empty", TorrentSetPayload{}},
+		{"empty ids", TorrentSetPayload{IDs: []int64{}}},
+		{"empty tracker list", TorrentSetPayload{IDs: []int64{1}, TrackerList: []string{}}},
+		{"all fields", fullTorrentSetPayload()},
+		{"hashes selector", TorrentSetPayload{Selector: &hashes, Labels: []string{"a"}}},
+		{"recently active selector", TorrentSetPayload{Selector: &recentlyActive, UploadLimited: new(bool)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := json.Marshal(test.payload)
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+			var decoded TorrentSetPayload
+			if err = json.Unmarshal(encoded, &decoded); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+			reencoded, err := json.Marshal(decoded)
+			if err != nil {
+				t.Fatalf("marshal of the decoded payload failed: %v", err)
+			}
+			if string(reencoded) != string(encoded) {
+				t.Errorf("round trip differs\n got: %s\nwant: %s", reencoded, encoded)
+			}
+		})
+	}
+}
+
+func TestTorrentSetPayloadUnmarshalKeepsAbsentFieldsNil(t *testing.T) {
+	var decoded TorrentSetPayload
+	if err := json.Unmarshal([]byte(`{"ids":[1],"seedIdleLimit":30,"trackerList":"a\n\nb"}`), &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if decoded.SeedIdleLimit == nil || decoded.SeedIdleLimit.Minutes() != 30 {
+		t.Errorf("got seed idle limit %v, want 30 minutes", decoded.SeedIdleLimit)
+	}
+	if want := []string{"a", "", "b"}; !reflect.DeepEqual(decoded.TrackerList, want) {
+		t.Errorf("got tracker list %q, want %q", decoded.TrackerList, want)
+	}
+	if decoded.DownloadLimit != nil || decoded.Labels != nil || decoded.SeedRatioLimit != nil || decoded.Selector != nil {
+		t.Errorf("absent fields are not nil: %+v", decoded)
+	}
+}
+
+func TestTorrentSetBatches(t *testing.T) {
+	var calls []map[string]json.RawMessage
+	client := newStubClient(t, &Config{MaxTorrentSetBatch: 100}, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		var arguments map[string]json.RawMessage
+		if err := json.Unmarshal(rq.Arguments, &arguments); err != nil {
+			t.Errorf("can't decode arguments: %v", err)
+		}
+		calls = append(calls, arguments)
+		writeStubAnswer(t, w, rq, nil)
+	})
+	ids := make([]int64, 250)
+	for index := range ids {
+		ids[index] = int64(index + 1)
+	}
+	limit := int64(0)
+	if err := client.TorrentSet(context.Background(), TorrentSetPayload{
+		IDs:           ids,
+		DownloadLimit: &limit,
+		Labels:        []string{"batched"},
+	}); err != nil {
+		t.Fatalf("TorrentSet() failed: %v", err)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("got %d torrent-set calls, want 3", len(calls))
+	}
+	var sent []int64
+	for index, arguments := range calls {
+		var batch []int64
+		if err := json.Unmarshal(arguments["ids"], &batch); err != nil {
+			t.Fatalf("call %d: can't decode ids: %v", index, err)
+		}
+		if want := []int{100, 100, 50}[index]; len(batch) != want {
+			t.Errorf("call %d: got %d ids, want %d", index, len(batch), want)
+		}
+		sent = append(sent, batch...)
+		if string(arguments["downloadLimit"]) != "0" || string(arguments["labels"]) != `["batched"]` || len(arguments) != 3 {
+			t.Errorf("call %d: mutators differ: %v", index, arguments)
+		}
+	}
+	if !reflect.DeepEqual(sent, ids) {
+		t.Errorf("ids sent differ from the ones given")
+	}
+}
+
+func TestTorrentSetPayloadValidateFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload TorrentSetPayload
+		err     string // empty if valid
+	}{
+		{"no files mutators", TorrentSetPayload{IDs: []int64{1}}, ""},
+		{"distinct indices", TorrentSetPayload{FilesWanted: []int64{0, 1}, FilesUnwanted: []int64{2}, PriorityHigh: []int64{0},
+			PriorityLow: []int64{1}, PriorityNormal: []int64{2}}, ""},
+		{"wanted and unwanted", TorrentSetPayload{FilesWanted: []int64{1, 3}, FilesUnwanted: []int64{3}},
+			"file index 3 appears in both files-wanted and files-unwanted"},
+		{"high and low", TorrentSetPayload{PriorityHigh: []int64{4}, PriorityLow: []int64{4}},
+			"file index 4 appears in both priority-high and priority-low"},
+		{"high and normal", TorrentSetPayload{PriorityHigh: []int64{5}, PriorityNormal: []int64{5}},
+			"file index 5 appears in both priority-high and priority-normal"},
+		{"low and normal", TorrentSetPayload{PriorityLow: []int64{6}, PriorityNormal: []int64{0, 6}},
+			"file index 6 appears in both priority-low and priority-normal"},
+		{"negative wanted", TorrentSetPayload{FilesWanted: []int64{-1}}, "file index -1 in files-wanted is negative"},
+		{"negative unwanted", TorrentSetPayload{FilesUnwanted: []int64{-2}}, "file index -2 in files-unwanted is negative"},
+		{"negative priority", TorrentSetPayload{PriorityNormal: []int64{-3}}, "file index -3 in priority-normal is negative"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.payload.validateFiles()
+			switch {
+			case test.err == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.err != "" && (err == nil || err.Error() != test.err):
+				t.Errorf("got error %v, want %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestTorrentSetSeedIdleLimit(t *testing.T) {
+	var sent map[string]json.RawMessage
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		sent = nil
+		if err := json.Unmarshal(rq.Arguments, &sent); err != nil {
+			t.Errorf("can't decode arguments: %v", err)
+		}
+		writeStubAnswer(t, w, rq, nil)
+	})
+	ninetySeconds, twoMinutes := 90*time.Second, 2*time.Minute
+	tests := []struct {
+		name  string
+		limit *time.Duration
+		sent  string // empty if omitted
+		err   bool
+	}{
+		{"90s refused", &ninetySeconds, "", true},
+		{"2m sent as 2", &twoMinutes, "2", false},
+		{"nil omitted", nil, "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sent = nil
+			err := client.TorrentSet(context.Background(), TorrentSetPayload{IDs: []int64{1}, SeedIdleLimit: test.limit})
+			if test.err {
+				if err == nil {
+					t.Error("TorrentSet() accepted a sub-minute limit")
+				}
+				if sent != nil {
+					t.Error("the payload has been sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TorrentSet() failed: %v", err)
+			}
+			value, present := sent["seedIdleLimit"]
+			switch {
+			case test.sent == "" && present:
+				t.Errorf("seedIdleLimit sent (%s) instead of omitted", value)
+			case test.sent != "" && string(value) != test.sent:
+				t.Errorf("got seedIdleLimit %s, want %s", value, test.sent)
+			}
+		})
+	}
+}
+
+func TestTorrentSetRoundsSeedIdleLimit(t *testing.T) {
+	var sent struct {
+		SeedIdleLimit int64 `json:"seedIdleLimit"`
+	}
+	client := newStubClient(t, &Config{RoundSeedIdleLimit: true}, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		if err := json.Unmarshal(rq.Arguments, &sent); err != nil {
+			t.Errorf("can't decode arguments: %v", err)
+		}
+		writeStubAnswer(t, w, rq, nil)
+	})
+	limit := 90 * time.Second
+	if err := client.TorrentSet(context.Background(), TorrentSetPayload{IDs: []int64{1}, SeedIdleLimit: &limit}); err != nil {
+		t.Fatalf("TorrentSet() failed: %v", err)
+	}
+	if sent.SeedIdleLimit != 1 {
+		t.Errorf("got seedIdleLimit %d, want 1", sent.SeedIdleLimit)
+	}
+}
+
+func TestTorrentReplaceTrackerOnPrivateTorrent(t *testing.T) {
+	var sets []string
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		switch rq.Method {
+		case MethodTorrentGet:
+			writeStubAnswer(t, w, rq, map[string]interface{}{"torrents": []map[string]interface{}{{
+				"id":        1,
+				"isPrivate": true,
+				"trackers": []map[string]interface{}{
+					{"announce": "https://private.example/old-passkey/announce", "id": 0, "tier": 0},
+					{"announce": "https://backup.example/announce", "id": 1, "tier": 1},
+				},
+			}}})
+		case MethodTorrentSet:
+			var arguments struct {
+				TrackerList string `json:"trackerList"`
+			}
+			if err := json.Unmarshal(rq.Arguments, &arguments); err != nil {
+				t.Errorf("can't decode arguments: %v", err)
+			}
+			sets = append(sets, arguments.TrackerList)
+			writeStubAnswer(t, w, rq, nil)
+		default:
+			t.Errorf("unexpected method '%s'", rq.Method)
+		}
+	})
+	ctx := context.Background()
+	if err := client.TorrentReplaceTracker(ctx, 1, "https://private.example/old-passkey/announce",
+		"https://private.example/new-passkey/announce"); err != nil {
+		t.Fatalf("TorrentReplaceTracker() failed: %v", err)
+	}
+	want := []string{"https://private.example/new-passkey/announce\n\nhttps://backup.example/announce"}
+	if !reflect.DeepEqual(sets, want) {
+		t.Errorf("got trackerLists %q, want %q", sets, want)
+	}
+	if err := client.TorrentAddTrackers(ctx, 1, 0, "https://public.example/announce"); !errors.Is(err, ErrPrivateTrackerEdit) {
+		t.Errorf("got error %v, want ErrPrivateTrackerEdit", err)
+	}
+	if len(sets) != 1 {
+		t.Errorf("got %d torrent-set, want the refused one not sent", len(sets))
+	}
+}
+
+func TestTorrentSetMultiConcurrencyBounded(t *testing.T) {
+	var inFlight, peak int32
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			seen := atomic.LoadInt32(&peak)
+			if current <= seen || atomic.CompareAndSwapInt32(&peak, seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		writeStubAnswer(t, w, rq, nil)
+	})
+	ops := make([]TorrentSetPayload, 20)
+	for index := range ops {
+		ops[index].IDs = []int64{int64(index + 1)}
+	}
+	if err := client.TorrentSetMulti(context.Background(), ops, true); err != nil {
+		t.Fatalf("TorrentSetMulti() failed: %v", err)
+	}
+	if peak := atomic.LoadInt32(&peak); peak > defaultBatchParallelism {
+		t.Errorf("got %d torrent-set in flight, want at most %d", peak, defaultBatchParallelism)
+	}
+}
@@ -2,7 +2,11 @@ package transmissionrpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"strings"
+	"time"
 )
 
 /*
@@ -13,7 +17,7 @@ import (
 // TorrentRemove allows to delete one or more torrents only or with their data.
 func (c *Client) TorrentRemove(ctx context.Context, payload TorrentRemovePayload) (err error) {
 	// Send payload
-	if err = c.rpcCall(ctx, "torrent-remove", payload, nil); err != nil {
+	if err = c.rpcCall(ctx, MethodTorrentRemove, payload, nil); err != nil {
 		return fmt.Errorf("'torrent-remove' rpc method failed: %w", err)
 	}
 	return
@@ -24,3 +28,120 @@ type TorrentRemovePayload struct {
 	IDs             []int64 `json:"ids"`
 	DeleteLocalData bool    `json:"delete-local-data"`
 }
+
+// CompletionCriteria selects the torrents removed by RemoveCompleted. Criteria left nil are ignored, the others must all be met.
+type CompletionCriteria struct {
+	MinRatio    *float64       // uploadRatio must be at least MinRatio
+	MinSeedTime *time.Duration // secondsSeeding must be at least MinSeedTime
+	Finished    *bool          // isFinished (seed ratio or idle limit reached) must match
+}
+
+// Matches returns true if the torrent meets all the set criteria. Fields needed by a set criterion
+// must have been requested, a missing one makes the torrent not match.
+func (cc CompletionCriteria) Matches(torrent Torrent) bool {
+	if cc.MinRatio != nil && (torrent.UploadRatio == nil || *torrent.UploadRatio < *cc.MinRatio) {
+		return false
+	}
+	if cc.MinSeedTime != nil && (torrent.TimeSeeding == nil || *torrent.TimeSeeding < *cc.MinSeedTime) {
+		return false
+	}
+	if cc.Finished != nil && (torrent.IsFinished == nil || *torrent.IsFinished != *cc.Finished) {
+		return false
+	}
+	return true
+}
+
+func (cc CompletionCriteria) isEmpty() bool {
+	return cc.MinRatio == nil && cc.MinSeedTime == nil && cc.Finished == nil
+}
+
+// RemoveCompleted removes the completed torrents (see Torrent.IsComplete()) meeting all the set criteria and returns their ids.
+// At least one criterion must be set. Torrents still downloading are never removed.
+func (c *Client) RemoveCompleted(ctx context.Context, criteria CompletionCriteria, deleteData bool) (removed []int64, err error) {
+	if criteria.isEmpty() {
+		err = errors.New("at least one completion criterion must be set")
+		return
+	}
+	torrents, err := c.torrentGet(ctx, []string{"id", "percentDone", "uploadRatio", "secondsSeeding", "isFinished"}, nil)
+	if err != nil {
+		return
+	}
+	for _, torrent := range torrents {
+		if torrent.ID == nil || !torrent.IsComplete() || !criteria.Matches(torrent) {
+			continue
+		}
+		removed = append(removed, *torrent.ID)
+	}
+	if len(removed) == 0 {
+		return
+	}
+	if err = c.TorrentRemove(ctx, TorrentRemovePayload{
+		IDs:             removed,
+		DeleteLocalData: deleteData,
+	}); err != nil {
+		removed = nil
+	}
+	return
+}
+
+// RemovalPreview describes what TorrentRemove would remove for one torrent, see TorrentRemovePreview().
+type RemovalPreview struct {
+	ID          int64
+	Name        string
+	HashString  string
+	DownloadDir string
+	TotalSize   int64 // bytes
+	// Paths are the top level paths (on the daemon host) deleted along with the torrent if DeleteLocalData is set:
+	// the torrent folder, or its single file.
+	Paths []string
+}
+
+// TorrentRemovePreview returns, without removing anything, what would be removed by TorrentRemove for the given ids.
+// A TorrentNotFoundError is returned if some of the ids are unknown.
+func (c *Client) TorrentRemovePreview(ctx context.Context, ids []int64) (previews []RemovalPreview, err error) {
+	if len(ids) == 0 {
+		err = errors.New("there must be at least one ID")
+		return
+	}
+	torrents, err := c.torrentGet(ctx, []string{"id", "name", "hashString", "downloadDir", "totalSize", "files"}, ids)
+	if err != nil {
+		return
+	}
+	found := make(map[int64]bool, len(torrents))
+	previews = make([]RemovalPreview, 0, len(torrents))
+	for _, torrent := range torrents {
+		if torrent.ID == nil {
+			continue
+		}
+		found[*torrent.ID] = true
+		preview := RemovalPreview{
+			ID:          *torrent.ID,
+			Name:        deref(torrent.Name),
+			HashString:  deref(torrent.HashString),
+			DownloadDir: deref(torrent.DownloadDir),
+		}
+		if torrent.TotalSize != nil {
+			preview.TotalSize = int64(torrent.TotalSize.Byte())
+		}
+		seen := make(map[string]bool)
+		for _, file := range torrent.Files {
+			top := strings.SplitN(file.Name, "/", 2)[0]
+			if top == "" || seen[top] {
+				continue
+			}
+			seen[top] = true
+			preview.Paths = append(preview.Paths, path.Join(preview.DownloadDir, top))
+		}
+		previews = append(previews, preview)
+	}
+	var missing []int64
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		err = TorrentNotFoundError{IDs: missing}
+	}
+	return
+}
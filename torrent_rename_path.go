@@ -2,7 +2,9 @@ package transmissionrpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 )
 
 /*
@@ -13,29 +15,43 @@ import (
 // TorrentRenamePath allows to rename torrent name or path.
 // 'path' is the path to the file or folder that will be renamed.
 // 'name' the file or folder's new name
+// A TorrentNotFoundError is returned if the torrent is unknown.
 func (c *Client) TorrentRenamePath(ctx context.Context, id int64, path, name string) (err error) {
-	if err = c.rpcCall(ctx, "torrent-rename-path", torrentRenamePathPayload{
+	if err = c.rpcCall(ctx, MethodTorrentRenamePath, torrentRenamePathPayload{
 		IDs:  []int64{id},
 		Path: path,
 		Name: name,
 	}, nil); err != nil {
+		if isRenameNotFound(err) {
+			err = TorrentNotFoundError{IDs: []int64{id}}
+		}
 		err = fmt.Errorf("'torrent-rename-path' rpc method failed: %w", err)
 	}
 	return
 }
 
 // TorrentRenamePathHash allows to rename torrent name or path by its hash.
+// A TorrentNotFoundError is returned if the torrent is unknown.
 func (c *Client) TorrentRenamePathHash(ctx context.Context, hash, path, name string) (err error) {
-	if err = c.rpcCall(ctx, "torrent-rename-path", torrentRenamePathHashPayload{
+	if err = c.rpcCall(ctx, MethodTorrentRenamePath, torrentRenamePathHashPayload{
 		Hashes: []string{hash},
 		Path:   path,
 		Name:   name,
 	}, nil); err != nil {
+		if isRenameNotFound(err) {
+			err = TorrentNotFoundError{Hashes: []string{hash}}
+		}
 		err = fmt.Errorf("'torrent-rename-path' rpc method failed: %w", err)
 	}
 	return
 }
 
+// isRenameNotFound detects the result the daemon answers when the torrent to rename is unknown.
+func isRenameNotFound(err error) bool {
+	var rpcErr RPCError
+	return errors.As(err, &rpcErr) && strings.Contains(rpcErr.Result, "requires 1 torrent")
+}
+
 type torrentRenamePathPayload struct {
 	IDs  []int64 `json:"ids"`  // the torrent torrent list, as described in 3.1 (must only be 1 torrent)
 	Path string  `json:"path"` // the path to the file or folder that will be renamed
@@ -0,0 +1,152 @@
+package transmissionrpc
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+/*
+	Torrents reports
+	Read only helpers built on torrent-get
+*/
+
+// DiskHealthReport returns the current corruptEver value (bytes of corrupt data ever downloaded) of each torrent and the
+// ids of the torrents whose corruptEver grew since prev (a previous report). Torrents absent from prev are not reported
+// as growing. A steady growth across torrents sharing the same disk usually indicates a failing disk.
+func (c *Client) DiskHealthReport(ctx context.Context, prev map[int64]int64) (current map[int64]int64, growing []int64, err error) {
+	torrents, err := c.torrentGet(ctx, []string{"id", "corruptEver"}, nil)
+	if err != nil {
+		return
+	}
+	current = make(map[int64]int64, len(torrents))
+	for _, torrent := range torrents {
+		if torrent.ID == nil || torrent.CorruptEver == nil {
+			continue
+		}
+		current[*torrent.ID] = *torrent.CorruptEver
+		if previous, known := prev[*torrent.ID]; known && *torrent.CorruptEver > previous {
+			growing = append(growing, *torrent.ID)
+		}
+	}
+	sort.Slice(growing, func(i, j int) bool { return growing[i] < growing[j] })
+	return
+}
+
+// StalePausedTorrents returns the stopped torrents without any progress (percentDone is 0) which were added more
+// than olderThan ago: usually forgotten imports added paused.
+func (c *Client) StalePausedTorrents(ctx context.Context, olderThan time.Duration) (stale []Torrent, err error) {
+	torrents, err := c.torrentGet(ctx, []string{"id", "name", "status", "percentDone", "addedDate"}, nil)
+	if err != nil {
+		return
+	}
+	threshold := time.Now().Add(-olderThan)
+	for _, torrent := range torrents {
+		if torrent.Status == nil || *torrent.Status != TorrentStatusStopped ||
+			torrent.PercentDone == nil || *torrent.PercentDone > 0 ||
+			torrent.AddedDate == nil || !torrent.AddedDate.Before(threshold) {
+			continue
+		}
+		stale = append(stale, torrent)
+	}
+	return
+}
+
+// TotalSizeWhenDone returns the sum, in bytes, of the sizeWhenDone (size of the wanted files only) of the given torrents
+// (all torrents if ids is empty). Unlike totalSize, it does not overestimate the space needed when files are unwanted.
+func (c *Client) TotalSizeWhenDone(ctx context.Context, ids []int64) (total int64, err error) {
+	torrents, err := c.torrentGet(ctx, []string{"id", "sizeWhenDone"}, ids)
+	if err != nil {
+		return
+	}
+	for _, torrent := range torrents {
+		if torrent.SizeWhenDone != nil {
+			total += int64(torrent.SizeWhenDone.Byte())
+		}
+	}
+	return
+}
+
+// StuckTorrents returns the downloading torrents whose remaining wanted data (leftUntilDone) can not be fully
+// downloaded from the connected peers (desiredAvailable is lower): they can not complete, whatever the bandwidth.
+func (c *Client) StuckTorrents(ctx context.Context) (stuck []Torrent, err error) {
+	torrents, err := c.torrentGet(ctx, []string{"id", "name", "status", "percentDone", "desiredAvailable", "leftUntilDone"}, nil)
+	if err != nil {
+		return
+	}
+	for _, torrent := range torrents {
+		if torrent.Status == nil || *torrent.Status != TorrentStatusDownload ||
+			torrent.DesiredAvailable == nil || torrent.LeftUntilDone == nil {
+			continue
+		}
+		if *torrent.DesiredAvailable < *torrent.LeftUntilDone {
+			stuck = append(stuck, torrent)
+		}
+	}
+	return
+}
+
+// StatusCounts returns the number of torrents in each status. Statuses without torrent are not present in the map.
+func (c *Client) StatusCounts(ctx context.Context) (counts map[TorrentStatus]int, err error) {
+	torrents, err := c.torrentGet(ctx, []string{"status"}, nil)
+	if err != nil {
+		return
+	}
+	counts = make(map[TorrentStatus]int)
+	for _, torrent := range torrents {
+		if torrent.Status != nil {
+			counts[*torrent.Status]++
+		}
+	}
+	return
+}
+
+// BandwidthShare is the part of the current session bandwidth used by a torrent, see BandwidthDistribution().
+type BandwidthShare struct {
+	ID            int64
+	Name          string
+	RateUpload    int64   // B/s
+	RateDownload  int64   // B/s
+	UploadShare   float64 // fraction [0, 1] of the total upload rate, 0 if nothing is uploaded
+	DownloadShare float64 // fraction [0, 1] of the total download rate, 0 if nothing is downloaded
+}
+
+// BandwidthDistribution returns the share of the total current upload and download rates used by each torrent,
+// ordered from the biggest upload consumer to the smallest (then by download share).
+func (c *Client) BandwidthDistribution(ctx context.Context) (shares []BandwidthShare, err error) {
+	torrents, err := c.torrentGet(ctx, []string{"id", "name", "rateUpload", "rateDownload"}, nil)
+	if err != nil {
+		return
+	}
+	var totalUpload, totalDownload int64
+	shares = make([]BandwidthShare, 0, len(torrents))
+	for _, torrent := range torrents {
+		if torrent.ID == nil {
+			continue
+		}
+		share := BandwidthShare{
+			ID:           *torrent.ID,
+			Name:         deref(torrent.Name),
+			RateUpload:   deref(torrent.RateUpload),
+			RateDownload: deref(torrent.RateDownload),
+		}
+		totalUpload += share.RateUpload
+		totalDownload += share.RateDownload
+		shares = append(shares, share)
+	}
+	for index := range shares {
+		if totalUpload > 0 {
+			shares[index].UploadShare = float64(shares[index].RateUpload) / float64(totalUpload)
+		}
+		if totalDownload > 0 {
+			shares[index].DownloadShare = float64(shares[index].RateDownload) / float64(totalDownload)
+		}
+	}
+	sort.SliceStable(shares, func(i, j int) bool {
+		if shares[i].RateUpload != shares[j].RateUpload {
+			return shares[i].RateUpload > shares[j].RateUpload
+		}
+		return shares[i].RateDownload > shares[j].RateDownload
+	})
+	return
+}
@@ -0,0 +1,223 @@
+package transmissionrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+/*
+	Torrent selector
+    https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md#31-torrent-action-requests
+*/
+
+const recentlyActiveSelector = "recently-active"
+
+// TorrentSelector represents the torrents targeted by a request: numeric ids and/or hashes, the recently active torrents
+// or all of them. Build it with IDs(), Hashes(), IDsAndHashes(), RecentlyActive() or All().
+type TorrentSelector struct {
+	ids            []int64
+	hashes         []string
+	recentlyActive bool
+	all            bool
+}
+
+// IDs selects torrents by their numeric ids.
+func IDs(ids ...int64) TorrentSelector {
+	return TorrentSelector{ids: ids}
+}
+
+// Hashes selects torrents by their SHA1 info hashes (which, unlike numeric ids, are stable across daemon restarts).
+func Hashes(hashes ...string) TorrentSelector {
+	return TorrentSelector{hashes: hashes}
+}
+
+// IDsAndHashes selects torrents by numeric ids and by hashes within the same request.
+func IDsAndHashes(ids []int64, hashes []string) TorrentSelector {
+	return TorrentSelector{ids: ids, hashes: hashes}
+}
+
+// All selects all the torrents: the ids argument is omitted from the request.
+func All() TorrentSelector {
+	return TorrentSelector{all: true}
+}
+
+// RecentlyActive selects the torrents which have been recently active.
+func RecentlyActive() TorrentSelector {
+	return TorrentSelector{recentlyActive: true}
+}
+
+// IsEmpty returns true if the selector does not select any torrent.
+func (ts TorrentSelector) IsEmpty() bool {
+	return !ts.all && !ts.recentlyActive && len(ts.ids) == 0 && len(ts.hashes) == 0
+}
+
+// IsAll returns true if the selector selects all the torrents.
+func (ts TorrentSelector) IsAll() bool {
+	return ts.all
+}
+
+// param returns the selector to marshal as the ids argument of a request, nil (to omit it) if it selects all the torrents.
+func (ts TorrentSelector) param() *TorrentSelector {
+	if ts.all {
+		return nil
+	}
+	return &ts
+}
+
+// MarshalJSON produces the wire form of the selector: the "recently-active" string or an array of ids and hashes.
+// All() is marshalled as null: requests omit the ids argument instead.
+func (ts TorrentSelector) MarshalJSON() (data []byte, err error) {
+	if ts.all {
+		return []byte("null"), nil
+	}
+	if ts.recentlyActive {
+		return json.Marshal(recentlyActiveSelector)
+	}
+	mixed := make([]interface{}, 0, len(ts.ids)+len(ts.hashes))
+	for _, id := range ts.ids {
+		mixed = append(mixed, id)
+	}
+	for _, hash := range ts.hashes {
+		mixed = append(mixed, hash)
+	}
+	return json.Marshal(mixed)
+}
+
+// UnmarshalJSON decodes the wire form of a selector.
+func (ts *TorrentSelector) UnmarshalJSON(data []byte) (err error) {
+	*ts = TorrentSelector{}
+	if data = bytes.TrimSpace(data); bytes.Equal(data, []byte("null")) {
+		ts.all = true
+		return
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var selector string
+		if err = json.Unmarshal(data, &selector); err != nil {
+			return
+		}
+		if selector != recentlyActiveSelector {
+			return fmt.Errorf("unknown torrent selector '%s'", selector)
+		}
+		ts.recentlyActive = true
+		return
+	}
+	var mixed []json.RawMessage
+	if err = json.Unmarshal(data, &mixed); err != nil {
+		return
+	}
+	var (
+		id   int64
+		hash string
+	)
+	for _, raw := range mixed {
+		if err = json.Unmarshal(raw, &id); err == nil {
+			ts.ids = append(ts.ids, id)
+			continue
+		}
+		if err = json.Unmarshal(raw, &hash); err != nil {
+			return fmt.Errorf("torrent selector element '%s' is neither an id nor a hash", raw)
+		}
+		ts.hashes = append(ts.hashes, hash)
+	}
+	return
+}
+
+type torrentGetSelectorParams struct {
+	Fields []string         `json:"fields"`
+	IDs    *TorrentSelector `json:"ids,omitempty"`
+}
+
+// TorrentGetSelection returns the given fields (mandatory) for each torrent of selector.
+func (c *Client) TorrentGetSelection(ctx context.Context, fields []string, selector TorrentSelector) (torrents []Torrent, err error) {
+	if err = c.validateTorrentFields(fields); err != nil {
+		return
+	}
+	if fields, err = c.gateTorrentGet(ctx, fields); err != nil {
+		return
+	}
+	return c.torrentGetSelector(ctx, fields, selector)
+}
+
+func (c *Client) torrentGetSelector(ctx context.Context, fields []string, selector TorrentSelector) (torrents []Torrent, err error) {
+	var result torrentGetResults
+	if err = c.rpcCall(ctx, MethodTorrentGet, torrentGetSelectorParams{
+		Fields: fields,
+		IDs:    selector.param(),
+	}, &result); err != nil {
+		err = fmt.Errorf("'torrent-get' rpc method failed: %w", err)
+		return
+	}
+	torrents = result.Torrents
+	c.pruneVerifyRunStates(torrents)
+	return
+}
+
+type torrentActionSelectorParam struct {
+	IDs *TorrentSelector `json:"ids,omitempty"`
+}
+
+// torrentActionSelector sends an action to the torrents of selector. Every torrent action goes through it, the run state
+// of the torrents to verify being recorded first (see TorrentCancelVerify()).
+func (c *Client) torrentActionSelector(ctx context.Context, method string, selector TorrentSelector) (err error) {
+	if selector.IsEmpty() {
+		return errors.New("the selector does not select any torrent (use All() to select them all)")
+	}
+	if method == MethodTorrentVerify {
+		c.recordVerifyRunStates(ctx, selector)
+	}
+	if err = c.rpcCall(ctx, method, torrentActionSelectorParam{IDs: selector.param()}, nil); err != nil {
+		err = fmt.Errorf("'%s' rpc method failed: %w", method, err)
+	}
+	return
+}
+
+// TorrentStartSelection starts the torrents of selector.
+func (c *Client) TorrentStartSelection(ctx context.Context, selector TorrentSelector) (err error) {
+	return c.torrentActionSelector(ctx, MethodTorrentStart, selector)
+}
+
+// TorrentStartNowSelection starts (now) the torrents of selector.
+func (c *Client) TorrentStartNowSelection(ctx context.Context, selector TorrentSelector) (err error) {
+	return c.torrentActionSelector(ctx, MethodTorrentStartNow, selector)
+}
+
+// TorrentStopSelection stops the torrents of selector.
+func (c *Client) TorrentStopSelection(ctx context.Context, selector TorrentSelector) (err error) {
+	return c.torrentActionSelector(ctx, MethodTorrentStop, selector)
+}
+
+// TorrentVerifySelection verifies the torrents of selector.
+func (c *Client) TorrentVerifySelection(ctx context.Context, selector TorrentSelector) (err error) {
+	return c.torrentActionSelector(ctx, MethodTorrentVerify, selector)
+}
+
+// TorrentReannounceSelection reannounces the torrents of selector.
+func (c *Client) TorrentReannounceSelection(ctx context.Context, selector TorrentSelector) (err error) {
+	return c.torrentActionSelector(ctx, MethodTorrentReannounce, selector)
+}
+
+type torrentRemoveSelectorPayload struct {
+	IDs             *TorrentSelector `json:"ids,omitempty"`
+	DeleteLocalData bool             `json:"delete-local-data"`
+}
+
+// TorrentRemoveSelection removes the torrents of selector, with their data if deleteData is true.
+// Removing all the torrents (All() selector) along with their data is refused: select them explicitly.
+func (c *Client) TorrentRemoveSelection(ctx context.Context, selector TorrentSelector, deleteData bool) (err error) {
+	if selector.IsEmpty() {
+		return errors.New("the selector does not select any torrent (use All() to select them all)")
+	}
+	if selector.IsAll() && deleteData {
+		return errors.New("removing all the torrents along with their data is refused: select them explicitly")
+	}
+	if err = c.rpcCall(ctx, MethodTorrentRemove, torrentRemoveSelectorPayload{
+		IDs:             selector.param(),
+		DeleteLocalData: deleteData,
+	}, nil); err != nil {
+		err = fmt.Errorf("'torrent-remove' rpc method failed: %w", err)
+	}
+	return
+}
@@ -0,0 +1,173 @@
+package transmissionrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTorrentSelectorEncoding(t *testing.T) {
+	hash := "0123456789abcdef0123456789abcdef01234567"
+	tests := []struct {
+		name     string
+		selector TorrentSelector
+		json     string // MarshalJSON()
+		payload  string // TorrentSetPayload.MarshalJSON()
+	}{
+		{"ids", IDs(1, 2), `[1,2]`, `{"ids":[1,2]}`},
+		{"hashes", Hashes(hash), `["` + hash + `"]`, `{"ids":["` + hash + `"]}`},
+		{"ids and hashes", IDsAndHashes([]int64{3}, []string{hash}), `[3,"` + hash + `"]`, `{"ids":[3,"` + hash + `"]}`},
+		{"recently active", RecentlyActive(), `"recently-active"`, `{"ids":"recently-active"}`},
+		{"all", All(), `null`, `{}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := json.Marshal(test.selector)
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+			if string(encoded) != test.json {
+				t.Errorf("got %s, want %s", encoded, test.json)
+			}
+			selector := test.selector
+			payload, err := TorrentSetPayload{Selector: &selector}.MarshalJSON()
+			if err != nil {
+				t.Fatalf("payload marshal failed: %v", err)
+			}
+			if string(payload) != test.payload {
+				t.Errorf("got payload %s, want %s", payload, test.payload)
+			}
+			var decoded TorrentSelector
+			if err = json.Unmarshal(encoded, &decoded); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, test.selector) {
+				t.Errorf("got decoded %+v, want %+v", decoded, test.selector)
+			}
+		})
+	}
+}
+
+func TestTorrentSelectorParam(t *testing.T) {
+	if All().param() != nil {
+		t.Error("All() must omit the ids argument")
+	}
+	if param := RecentlyActive().param(); param == nil || !param.recentlyActive {
+		t.Error("RecentlyActive() must be sent")
+	}
+}
+
+func TestTorrentSelectorUnknownString(t *testing.T) {
+	var selector TorrentSelector
+	if err := json.Unmarshal([]byte(`"everything"`), &selector); err == nil {
+		t.Error("unknown selector string accepted")
+	}
+}
+
+func TestTorrentSetRefusedSelections(t *testing.T) {
+	client, err := New(mustParseURL(t, "http://127.0.0.1:1/transmission/rpc"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty, all := IDs(), All()
+	for name, payload := range map[string]TorrentSetPayload{
+		"no ids":         {},
+		"empty selector": {Selector: &empty},
+		"all":            {Selector: &all},
+	} {
+		if err = client.TorrentSet(context.Background(), payload); err == nil {
+			t.Errorf("%s: TorrentSet() accepted the selection", name)
+		}
+	}
+	if err = client.TorrentRemoveSelection(context.Background(), All(), true); err == nil {
+		t.Error("TorrentRemoveSelection() accepted to remove all the torrents with their data")
+	}
+}
+
+func TestLegacyVerbsMatchSelection(t *testing.T) {
+	var sent []string
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		sent = append(sent, rq.Method+" "+string(rq.Arguments))
+		writeStubAnswer(t, w, rq, nil)
+	})
+	ctx := context.Background()
+	hash := "0123456789abcdef0123456789abcdef01234567"
+	pairs := []struct {
+		name             string
+		legacy, selected func() error
+	}{
+		{"ids", func() error { return client.TorrentStopIDs(ctx, []int64{1, 2}) },
+			func() error { return client.TorrentStopSelection(ctx, IDs(1, 2)) }},
+		{"no ids", func() error { return client.TorrentStartIDs(ctx, nil) },
+			func() error { return client.TorrentStartSelection(ctx, All()) }},
+		{"hashes", func() error { return client.TorrentReannounceHashes(ctx, []string{hash}) },
+			func() error { return client.TorrentReannounceSelection(ctx, Torrents(Hash(hash))) }},
+		{"recently active", func() error { return client.TorrentStartNowRecentlyActive(ctx) },
+			func() error { return client.TorrentStartNowSelection(ctx, RecentlyActive()) }},
+		{"location", func() error { return client.TorrentSetLocation(ctx, 3, "/data", true) },
+			func() error { return client.TorrentSetLocationSelection(ctx, Torrents(ID(3)), "/data", true) }},
+	}
+	for _, pair := range pairs {
+		sent = nil
+		if err := pair.legacy(); err != nil {
+			t.Fatalf("%s: legacy call failed: %v", pair.name, err)
+		}
+		if err := pair.selected(); err != nil {
+			t.Fatalf("%s: selection call failed: %v", pair.name, err)
+		}
+		if len(sent) != 2 || sent[0] != sent[1] {
+			t.Errorf("%s: got requests %q, want twice the same", pair.name, sent)
+		}
+	}
+}
+
+func TestTorrentSetLocationRequiresAbsolutePath(t *testing.T) {
+	client, err := New(mustParseURL(t, "http://127.0.0.1:1/transmission/rpc"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, location := range []string{"", "relative/dir", "C:relative"} {
+		if err = client.TorrentSetLocationSelection(context.Background(), IDs(1), location, true); !errors.Is(err, ErrInvalidLocation) {
+			t.Errorf("location %q: got error %v, want ErrInvalidLocation", location, err)
+		}
+		if err = client.TorrentSetLocation(context.Background(), 1, location, false); !errors.Is(err, ErrInvalidLocation) {
+			t.Errorf("location %q: TorrentSetLocation() got error %v, want ErrInvalidLocation", location, err)
+		}
+	}
+}
+
+func TestBatchAndTorrentSetMultiErrors(t *testing.T) {
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		if rq.Method == MethodTorrentStop || rq.Method == MethodTorrentSet && strings.Contains(string(rq.Arguments), `"ids":[2]`) {
+			fmt.Fprintf(w, `{"arguments":{},"result":"daemon is busy","tag":%d}`, rq.Tag)
+			return
+		}
+		writeStubAnswer(t, w, rq, nil)
+	})
+	ctx := context.Background()
+	var multiErr MultiError
+	err := client.NewBatch().TorrentStart(1).TorrentStop(2, 3).Commit(ctx)
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("got error %v, want a MultiError", err)
+	}
+	if len(multiErr.Failed) != 1 || multiErr.Failed[0].Method != MethodTorrentStop || !reflect.DeepEqual(multiErr.Failed[0].IDs, []int64{2, 3}) {
+		t.Errorf("unexpected batch failures: %+v", multiErr.Failed)
+	}
+	err = client.TorrentSetMulti(ctx, []TorrentSetPayload{
+		NewTorrentSetPayload([]int64{1}, WithUploadLimit(5)),
+		NewTorrentSetPayload([]int64{2}, WithUploadLimit(5)),
+	}, false)
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("got error %v, want a MultiError", err)
+	}
+	if len(multiErr.Failed) != 1 || multiErr.Failed[0].Index != 1 || multiErr.Failed[0].Method != MethodTorrentSet {
+		t.Errorf("unexpected torrent-set failures: %+v", multiErr.Failed)
+	}
+}
@@ -2,7 +2,11 @@ package transmissionrpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"strings"
+	"time"
 )
 
 /*
@@ -14,22 +18,33 @@ import (
 // 'location' is the new torrent location.
 // 'move' if true, move from previous location. Otherwise, search "location" for file.
 func (c *Client) TorrentSetLocation(ctx context.Context, id int64, location string, move bool) (err error) {
-	if err = c.rpcCall(ctx, "torrent-set-location", torrentSetLocationPayload{
-		IDs:      []int64{id},
-		Location: location,
-		Move:     move,
-	}, nil); err != nil {
-		err = fmt.Errorf("'torrent-set-location' rpc method failed: %w", err)
-	}
-	return
+	return c.TorrentSetLocationSelection(ctx, IDs(id), location, move)
 }
 
 // TorrentSetLocationHash allows to set a new location for one or more torrents.
 // 'location' is the new torrent location.
 // 'move' if true, move from previous location. Otherwise, search "location" for file.
 func (c *Client) TorrentSetLocationHash(ctx context.Context, hash, location string, move bool) (err error) {
-	if err = c.rpcCall(ctx, "torrent-set-location", torrentSetLocationHashPayload{
-		Hashes:   []string{hash},
+	return c.TorrentSetLocationSelection(ctx, Hashes(hash), location, move)
+}
+
+// ErrInvalidLocation is returned when a new torrent location is empty or not absolute.
+var ErrInvalidLocation = errors.New("location must be a non empty absolute path")
+
+// TorrentSetLocationSelection sets a new location for the torrents of selector. location must be an absolute path
+// on the daemon host, ErrInvalidLocation is returned otherwise. If move is true, the data is moved from the previous
+// location, otherwise the daemon looks for the data within location.
+func (c *Client) TorrentSetLocationSelection(ctx context.Context, selector TorrentSelector, location string, move bool) (err error) {
+	// Validate
+	if selector.IsEmpty() {
+		return errors.New("the selector does not select any torrent (use All() to select them all)")
+	}
+	if !isAbsoluteRemotePath(location) {
+		return fmt.Errorf("can't set torrents location to '%s': %w", location, ErrInvalidLocation)
+	}
+	// Send payload
+	if err = c.rpcCall(ctx, MethodTorrentSetLocation, torrentSetLocationPayload{
+		IDs:      selector.param(),
 		Location: location,
 		Move:     move,
 	}, nil); err != nil {
@@ -38,14 +53,67 @@ func (c *Client) TorrentSetLocationHash(ctx context.Context, hash, location stri
 	return
 }
 
-type torrentSetLocationPayload struct {
-	IDs      []int64 `json:"ids"`      // torrent list
-	Location string  `json:"location"` // the new torrent location
-	Move     bool    `json:"move"`     // if true, move from previous location. Otherwise, search "location" for files
+// TorrentMoveData moves the data of several torrents to newDir (torrent-set-location with move set to true).
+// newDir must be an absolute path on the daemon host, ErrInvalidLocation is returned otherwise.
+// The daemon moves the data asynchronously, use TorrentMoveDataWait to wait for the moves to complete.
+func (c *Client) TorrentMoveData(ctx context.Context, ids []int64, newDir string) (err error) {
+	if len(ids) == 0 {
+		return errors.New("there must be at least one ID")
+	}
+	return c.TorrentSetLocationSelection(ctx, IDs(ids...), newDir, true)
 }
 
-type torrentSetLocationHashPayload struct {
-	Hashes   []string `json:"ids"`      // torrent list
-	Location string   `json:"location"` // the new torrent location
-	Move     bool     `json:"move"`     // if true, move from previous location. Otherwise, search "location" for files
+// TorrentMoveDataWait does the same as TorrentMoveData but also waits for the moves to complete by polling
+// the torrents every pollInterval: a move is considered done when the torrent reports newDir as its download
+// dir and is not checking (or waiting to check) its files anymore. Cancel ctx to stop waiting.
+func (c *Client) TorrentMoveDataWait(ctx context.Context, ids []int64, newDir string, pollInterval time.Duration) (err error) {
+	if err = c.TorrentMoveData(ctx, ids, newDir); err != nil {
+		return
+	}
+	if pollInterval <= 0 {
+		return errors.New("poll interval must be positive")
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	var (
+		torrents []Torrent
+		moving   bool
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for torrents data to be moved: %w", ctx.Err())
+		case <-ticker.C:
+		}
+		if torrents, err = c.fetchTorrents(ctx, []string{"id", "downloadDir", "status"}, ids); err != nil {
+			return
+		}
+		moving = false
+		for _, torrent := range torrents {
+			if torrent.DownloadDir == nil || path.Clean(*torrent.DownloadDir) != path.Clean(newDir) ||
+				torrent.Status == nil || *torrent.Status == TorrentStatusCheckWait || *torrent.Status == TorrentStatusCheck {
+				moving = true
+				break
+			}
+		}
+		if !moving {
+			return
+		}
+	}
+}
+
+// isAbsoluteRemotePath checks if location is absolute regardless of the daemon host OS (unix or windows style).
+func isAbsoluteRemotePath(location string) bool {
+	if strings.HasPrefix(location, "/") {
+		return true
+	}
+	// windows drive letter: C:\ or C:/
+	return len(location) >= 3 && location[1] == ':' && (location[2] == '\\' || location[2] == '/') &&
+		((location[0] >= 'a' && location[0] <= 'z') || (location[0] >= 'A' && location[0] <= 'Z'))
+}
+
+type torrentSetLocationPayload struct {
+	IDs      *TorrentSelector `json:"ids,omitempty"` // torrent list
+	Location string           `json:"location"`      // the new torrent location
+	Move     bool             `json:"move"`          // if true, move from previous location. Otherwise, search "location" for files
 }
@@ -0,0 +1,47 @@
+package transmissionrpc
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+	Multiple torrent-set
+	Apply distinct mutators to distinct torrents
+*/
+
+// TorrentSetMulti sends each payload with TorrentSet (same connection pool and session id). Every payload is sent even
+// if others fail: failures are returned together as a MultiError. If concurrent is true payloads are sent up to 4 at
+// once as for a Batch, within the client limits (see Config.MaxConcurrentRPC and Config.RateLimit), otherwise in order.
+// Unlike a Batch, payloads are sent as is: use a Batch to merge the ones carrying the same mutators.
+func (c *Client) TorrentSetMulti(ctx context.Context, ops []TorrentSetPayload, concurrent bool) (err error) {
+	errs := make([]error, len(ops))
+	if concurrent {
+		slots := make(chan struct{}, defaultBatchParallelism)
+		var workers sync.WaitGroup
+		workers.Add(len(ops))
+		for index := range ops {
+			slots <- struct{}{}
+			go func(index int) {
+				defer func() {
+					<-slots
+					workers.Done()
+				}()
+				if errs[index] = ctx.Err(); errs[index] == nil {
+					errs[index] = c.TorrentSet(ctx, ops[index])
+				}
+			}(index)
+		}
+		workers.Wait()
+	} else {
+		for index := range ops {
+			if errs[index] = ctx.Err(); errs[index] == nil {
+				errs[index] = c.TorrentSet(ctx, ops[index])
+			}
+		}
+	}
+	err = newMultiError(errs, func(index int) (method string, ids []int64) {
+		return MethodTorrentSet, ops[index].IDs
+	})
+	return
+}
@@ -0,0 +1,185 @@
+package transmissionrpc
+
+import (
+	"time"
+)
+
+/*
+	Torrent Mutators options
+	Build a TorrentSetPayload from plain values
+*/
+
+// TorrentSetOption sets one mutator of a TorrentSetPayload, see NewTorrentSetPayload().
+type TorrentSetOption func(payload *TorrentSetPayload)
+
+// NewTorrentSetPayload returns a TorrentSetPayload for ids with the given mutators set. Options take plain values
+// (zero values included, for example WithDownloadLimit(0)) and handle the pointers of the payload.
+func NewTorrentSetPayload(ids []int64, opts ...TorrentSetOption) (payload TorrentSetPayload) {
+	payload.IDs = ids
+	for _, opt := range opts {
+		opt(&payload)
+	}
+	return
+}
+
+// WithBandwidthPriority sets the torrent bandwidth priority.
+func WithBandwidthPriority(priority int64) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.BandwidthPriority = &priority
+	}
+}
+
+// WithDownloadLimit sets the torrent maximum download speed (KBps) and enables it, unless WithDownloadLimited() is also used.
+func WithDownloadLimit(limit int64) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.DownloadLimit = &limit
+		if payload.DownloadLimited == nil {
+			limited := true
+			payload.DownloadLimited = &limited
+		}
+	}
+}
+
+// WithDownloadLimited sets whether the torrent download limit is honored.
+func WithDownloadLimited(limited bool) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.DownloadLimited = &limited
+	}
+}
+
+// WithFilesWanted sets the indices of the files to download.
+func WithFilesWanted(indices ...int64) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.FilesWanted = indices
+	}
+}
+
+// WithFilesUnwanted sets the indices of the files to not download.
+func WithFilesUnwanted(indices ...int64) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.FilesUnwanted = indices
+	}
+}
+
+// WithGroup sets the bandwidth group of the torrent.
+func WithGroup(group string) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.Group = &group
+	}
+}
+
+// WithHonorsSessionLimits sets whether the session limits are honored.
+func WithHonorsSessionLimits(honors bool) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.HonorsSessionLimits = &honors
+	}
+}
+
+// WithLabels sets the torrent labels.
+func WithLabels(labels ...string) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.Labels = labels
+	}
+}
+
+// WithLocation sets the new location of the torrent content.
+func WithLocation(location string) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.Location = &location
+	}
+}
+
+// WithPeerLimit sets the torrent maximum number of peers.
+func WithPeerLimit(limit int64) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.PeerLimit = &limit
+	}
+}
+
+// WithPriorityHigh sets the indices of the high priority files.
+func WithPriorityHigh(indices ...int64) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.PriorityHigh = indices
+	}
+}
+
+// WithPriorityLow sets the indices of the low priority files.
+func WithPriorityLow(indices ...int64) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.PriorityLow = indices
+	}
+}
+
+// WithPriorityNormal sets the indices of the normal priority files.
+func WithPriorityNormal(indices ...int64) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.PriorityNormal = indices
+	}
+}
+
+// WithQueuePosition sets the torrent position in its queue.
+func WithQueuePosition(position int64) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.QueuePosition = &position
+	}
+}
+
+// WithSeedIdleLimit sets the torrent seeding inactivity limit (a whole number of minutes, see Config.RoundSeedIdleLimit).
+func WithSeedIdleLimit(limit time.Duration) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.SeedIdleLimit = &limit
+	}
+}
+
+// WithSeedIdleMode sets the seeding inactivity mode (see SeedIdleMode constants).
+func WithSeedIdleMode(mode int64) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.SeedIdleMode = &mode
+	}
+}
+
+// WithSeedRatioLimit sets the torrent seeding ratio limit.
+func WithSeedRatioLimit(ratio float64) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.SeedRatioLimit = &ratio
+	}
+}
+
+// WithSeedRatioMode sets the seeding ratio mode.
+func WithSeedRatioMode(mode SeedRatioMode) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.SeedRatioMode = &mode
+	}
+}
+
+// WithSequentialDownload sets whether the torrent pieces are downloaded in order (RPC v18).
+func WithSequentialDownload(sequential bool) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.SequentialDownload = &sequential
+	}
+}
+
+// WithTrackerList sets the torrent announce URLs (an empty string between tiers).
+func WithTrackerList(trackerList []string) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.TrackerList = trackerList
+	}
+}
+
+// WithUploadLimit sets the torrent maximum upload speed (KBps) and enables it, unless WithUploadLimited() is also used.
+func WithUploadLimit(limit int64) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.UploadLimit = &limit
+		if payload.UploadLimited == nil {
+			limited := true
+			payload.UploadLimited = &limited
+		}
+	}
+}
+
+// WithUploadLimited sets whether the torrent upload limit is honored.
+func WithUploadLimited(limited bool) TorrentSetOption {
+	return func(payload *TorrentSetPayload) {
+		payload.UploadLimited = &limited
+	}
+}
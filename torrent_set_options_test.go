@@ -0,0 +1,55 @@
+package transmissionrpc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWithDownloadLimitZero(t *testing.T) {
+	encoded, err := json.Marshal(NewTorrentSetPayload([]int64{1}, WithDownloadLimit(0)))
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if want := `{"downloadLimit":0,"downloadLimited":true,"ids":[1]}`; string(encoded) != want {
+		t.Errorf("got %s, want %s", encoded, want)
+	}
+}
+
+func TestWithLimitedOverridesLimit(t *testing.T) {
+	for _, opts := range [][]TorrentSetOption{
+		{WithUploadLimit(10), WithUploadLimited(false)},
+		{WithUploadLimited(false), WithUploadLimit(10)},
+	} {
+		payload := NewTorrentSetPayload([]int64{1}, opts...)
+		if payload.UploadLimited == nil || *payload.UploadLimited {
+			t.Errorf("WithUploadLimited(false) has been overridden: %v", payload.UploadLimited)
+		}
+	}
+}
+
+func TestTorrentSetOptionsMatchHandBuiltPayload(t *testing.T) {
+	var (
+		limit   int64 = 0
+		limited       = false
+		idle          = 2 * time.Minute
+	)
+	handBuilt := TorrentSetPayload{
+		IDs:             []int64{1, 2},
+		DownloadLimit:   &limit,
+		DownloadLimited: &limited,
+		Labels:          []string{"a", "b"},
+		SeedIdleLimit:   &idle,
+		TrackerList:     []string{"https://a/announce"},
+	}
+	built := NewTorrentSetPayload([]int64{1, 2}, WithDownloadLimit(0), WithDownloadLimited(false), WithLabels("a", "b"),
+		WithSeedIdleLimit(idle), WithTrackerList([]string{"https://a/announce"}))
+	want, _ := json.Marshal(handBuilt)
+	got, err := json.Marshal(built)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+package transmissionrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*
+	Trackers helpers
+	Built on torrent-get trackers/trackerStats fields and torrent-set trackerList mutator
+*/
+
+// FailingTrackers returns, for each torrent having at least one failing tracker, the list of failing trackers
+// formatted as "host: last announce result". A tracker is failing if its last announce did not succeed (or timed out).
+// Trackers which never announced are not considered failing.
+func (c *Client) FailingTrackers(ctx context.Context) (failing map[int64][]string, err error) {
+	torrents, err := c.torrentGet(ctx, []string{"id", "trackerStats"}, nil)
+	if err != nil {
+		return
+	}
+	failing = make(map[int64][]string)
+	for _, torrent := range torrents {
+		if torrent.ID == nil {
+			continue
+		}
+		for _, stats := range torrent.TrackerStats {
+			if !stats.IsFailing() {
+				continue
+			}
+			result := stats.LastAnnounceResult
+			if result == "" && stats.LastAnnounceTimedOut {
+				result = "timed out"
+			}
+			failing[*torrent.ID] = append(failing[*torrent.ID], fmt.Sprintf("%s: %s", stats.Host, result))
+		}
+	}
+	return
+}
+
+// IsFailing returns true if the tracker has announced at least once and its last announce did not succeed.
+func (ts *TrackerStats) IsFailing() bool {
+	return ts.HasAnnounced && (!ts.LastAnnounceSucceeded || ts.LastAnnounceTimedOut)
+}
+
+// ErrPrivateTrackerEdit is returned when trying to add trackers to a private torrent while Config.AllowPrivateTrackerEdit is not set.
+var ErrPrivateTrackerEdit = errors.New("adding trackers to a private torrent is not allowed")
+
+// checkPrivateTrackerEdit verifies that trackerList does not add new trackers to any private torrent within selector.
+// A new announce URL replacing (see replacements) one of the torrent trackers is not considered as added.
+func (c *Client) checkPrivateTrackerEdit(ctx context.Context, selector TorrentSelector, trackerList []string,
+	replacements map[string]string) (err error) {
+	torrents, err := c.torrentGetSelector(ctx, []string{"id", "isPrivate", "trackers"}, selector)
+	if err != nil {
+		return fmt.Errorf("can't check if torrents are private: %w", err)
+	}
+	var known map[string]bool
+	for _, torrent := range torrents {
+		if torrent.IsPrivate == nil || !*torrent.IsPrivate {
+			continue
+		}
+		known = make(map[string]bool, len(torrent.Trackers))
+		for _, tracker := range torrent.Trackers {
+			known[tracker.Announce] = true
+		}
+		for _, announce := range trackerList {
+			if replaced, found := replacements[announce]; found && known[replaced] {
+				continue
+			}
+			if announce != "" && !known[announce] {
+				return fmt.Errorf("torrent %d: '%s': %w", *torrent.ID, announce, ErrPrivateTrackerEdit)
+			}
+		}
+	}
+	return
+}
+
+// torrentTrackerTiers fetches the trackers of a torrent grouped by tier.
+func (c *Client) torrentTrackerTiers(ctx context.Context, id int64) (tiers [][]string, err error) {
+	torrent, err := c.torrentGetOne(ctx, []string{"id", "trackers"}, id)
+	if err != nil {
+		return
+	}
+	if tiers = torrent.TrackerTiers(); tiers == nil {
+		tiers = make([][]string, 0)
+	}
+	return
+}
+
+// setTorrentTrackerTiers replaces the trackers of a torrent by tiers.
+func (c *Client) setTorrentTrackerTiers(ctx context.Context, id int64, tiers [][]string) (err error) {
+	return c.TorrentSet(ctx, TorrentSetPayload{
+		IDs:         []int64{id},
+		TrackerList: TrackerListFromTiers(tiers),
+	})
+}
+
+// TorrentAddTrackers adds announce URLs to the tier of index tier (starting at 0) of a torrent, a new last tier being
+// created if the tier does not exist (for example with a negative index). Announce URLs already used by the torrent, in any
+// tier, are skipped. The other trackers and their tiers are kept as is. Nothing is sent if there is nothing to add.
+func (c *Client) TorrentAddTrackers(ctx context.Context, id int64, tier int, announces ...string) (err error) {
+	tiers, err := c.torrentTrackerTiers(ctx, id)
+	if err != nil {
+		return
+	}
+	known := make(map[string]bool)
+	for _, trackers := range tiers {
+		for _, announce := range trackers {
+			known[announce] = true
+		}
+	}
+	var added []string
+	for _, announce := range announces {
+		if announce = strings.TrimSpace(announce); announce != "" && !known[announce] {
+			known[announce] = true
+			added = append(added, announce)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+	if tier < 0 || tier >= len(tiers) {
+		tiers = append(tiers, added)
+	} else {
+		tiers[tier] = append(tiers[tier], added...)
+	}
+	return c.setTorrentTrackerTiers(ctx, id, tiers)
+}
+
+// TorrentRemoveTrackers removes announce URLs from a torrent, whatever their tier. Tiers left empty disappear,
+// the others are kept as is. Nothing is sent if none of the announce URLs is used by the torrent.
+func (c *Client) TorrentRemoveTrackers(ctx context.Context, id int64, announces ...string) (err error) {
+	tiers, err := c.torrentTrackerTiers(ctx, id)
+	if err != nil {
+		return
+	}
+	removed := make(map[string]bool, len(announces))
+	for _, announce := range announces {
+		removed[strings.TrimSpace(announce)] = true
+	}
+	found := false
+	for index, trackers := range tiers {
+		kept := make([]string, 0, len(trackers))
+		for _, announce := range trackers {
+			if removed[announce] {
+				found = true
+				continue
+			}
+			kept = append(kept, announce)
+		}
+		tiers[index] = kept
+	}
+	if !found {
+		return
+	}
+	return c.setTorrentTrackerTiers(ctx, id, tiers)
+}
+
+// TorrentReplaceTracker replaces the oldAnnounce URL of a torrent by newAnnounce, within the same tier
+// (for example when a tracker changes its domain or a passkey is renewed). An error is returned if the torrent
+// does not use oldAnnounce. Being a one for one swap, it is allowed on private torrents even if Config.AllowPrivateTrackerEdit
+// is not set.
+func (c *Client) TorrentReplaceTracker(ctx context.Context, id int64, oldAnnounce, newAnnounce string) (err error) {
+	oldAnnounce, newAnnounce = strings.TrimSpace(oldAnnounce), strings.TrimSpace(newAnnounce)
+	if newAnnounce == "" {
+		return errors.New("new announce URL can not be empty")
+	}
+	tiers, err := c.torrentTrackerTiers(ctx, id)
+	if err != nil {
+		return
+	}
+	found := false
+	for _, trackers := range tiers {
+		for index, announce := range trackers {
+			if announce == oldAnnounce {
+				trackers[index] = newAnnounce
+				found = true
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("torrent %d does not use the '%s' tracker", id, oldAnnounce)
+	}
+	return c.torrentSet(ctx, TorrentSetPayload{
+		IDs:         []int64{id},
+		TrackerList: TrackerListFromTiers(tiers),
+	}, map[string]string{newAnnounce: oldAnnounce})
+}
@@ -0,0 +1,227 @@
+package transmissionrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+	Synchronous verification
+	Built on torrent-verify and torrent-get polling
+*/
+
+const (
+	verifyPollInterval = time.Second
+	// verifyStartGrace is how long VerifyTorrent waits for the daemon to report the torrent as checking (or waiting
+	// to): a verification not seen by then is considered done between two polls.
+	verifyStartGrace = 3 * verifyPollInterval
+)
+
+var (
+	// ErrVerifyQueuedTimeout is returned by VerifyTorrent when the timeout is reached while the torrent is still waiting in the verify queue.
+	ErrVerifyQueuedTimeout = errors.New("timeout reached while the torrent was waiting to be verified")
+	// ErrVerifyTimeout is returned by VerifyTorrent when the timeout is reached while the torrent is being verified.
+	ErrVerifyTimeout = errors.New("timeout reached while the torrent was being verified")
+)
+
+// VerifyTorrent triggers the verification of a torrent and blocks until the verification is over or timeout is reached.
+// valid is true if, once verified, all the wanted data is present (percentDone is 1) and no new corrupted data was found.
+// If the timeout is reached, ErrVerifyQueuedTimeout or ErrVerifyTimeout is returned depending on the torrent state.
+// The verification is considered over once the torrent has been seen checking (or waiting to) and is not anymore,
+// or if it was never seen checking within a few seconds (a verification too quick to be seen by the polling).
+func (c *Client) VerifyTorrent(ctx context.Context, id int64, timeout time.Duration) (valid bool, err error) {
+	fields := []string{"id", "status", "percentDone", "corruptEver"}
+	// Get the initial state
+	before, err := c.torrentGetOne(ctx, fields, id)
+	if err != nil {
+		return
+	}
+	// Start verification
+	if err = c.TorrentVerifyIDs(ctx, []int64{id}); err != nil {
+		return
+	}
+	// Wait for completion
+	verifyCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(verifyPollInterval)
+	defer ticker.Stop()
+	var (
+		lastStatus = TorrentStatusCheckWait
+		requested  = time.Now()
+		checking   bool // the daemon reported the torrent as checking or waiting to, at least once
+		current    Torrent
+	)
+	for {
+		if current, err = c.torrentGetOne(verifyCtx, fields, id); err != nil {
+			if ctx.Err() == nil && verifyCtx.Err() != nil {
+				err = verifyTimeoutError(lastStatus)
+			}
+			return
+		}
+		if current.Status == nil {
+			err = errors.New("torrent status is missing from the torrent-get answer")
+			return
+		}
+		if *current.Status == TorrentStatusCheckWait || *current.Status == TorrentStatusCheck {
+			checking = true
+			lastStatus = *current.Status
+		} else if checking || time.Since(requested) >= verifyStartGrace {
+			break
+		}
+		select {
+		case <-ticker.C:
+		case <-verifyCtx.Done():
+			if err = ctx.Err(); err == nil {
+				err = verifyTimeoutError(lastStatus)
+			}
+			return
+		}
+	}
+	// Check results
+	valid = current.IsComplete() && (before.CorruptEver == nil || current.CorruptEver == nil || *current.CorruptEver <= *before.CorruptEver)
+	return
+}
+
+func verifyTimeoutError(status TorrentStatus) error {
+	if status == TorrentStatusCheckWait {
+		return ErrVerifyQueuedTimeout
+	}
+	return ErrVerifyTimeout
+}
+
+// verifyRunStates records, for the torrents whose verification has been requested through the client,
+// whether they were running before it, see TorrentCancelVerify().
+type verifyRunStates struct {
+	running map[int64]verifyRunState
+	access  sync.Mutex
+}
+
+type verifyRunState struct {
+	running   bool
+	requested time.Time
+}
+
+// reset forgets every recorded state (the torrent ids are not valid anymore after a daemon restart).
+func (vrs *verifyRunStates) reset() {
+	defer vrs.access.Unlock()
+	vrs.access.Lock()
+	vrs.running = nil
+}
+
+// recordVerifyRunStates records the run state of the torrents of selector before their verification is requested.
+// Torrents already being verified keep the state recorded when their verification was first requested.
+// This is best effort: the verification is requested anyway if the torrents can't be fetched.
+func (c *Client) recordVerifyRunStates(ctx context.Context, selector TorrentSelector) {
+	torrents, err := c.torrentGetSelector(ctx, []string{"id", "status"}, selector)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	defer c.verifyRunStates.access.Unlock()
+	c.verifyRunStates.access.Lock()
+	if c.verifyRunStates.running == nil {
+		c.verifyRunStates.running = make(map[int64]verifyRunState, len(torrents))
+	}
+	for _, torrent := range torrents {
+		if torrent.ID == nil || torrent.Status == nil {
+			continue
+		}
+		if _, recorded := c.verifyRunStates.running[*torrent.ID]; recorded &&
+			(*torrent.Status == TorrentStatusCheckWait || *torrent.Status == TorrentStatusCheck) {
+			continue
+		}
+		c.verifyRunStates.running[*torrent.ID] = verifyRunState{
+			running:   *torrent.Status != TorrentStatusStopped,
+			requested: now,
+		}
+	}
+}
+
+// pruneVerifyRunStates forgets the recorded states of the torrents seen out of verification: their verification is
+// over (once the daemon had time to start it, see verifyStartGrace).
+func (c *Client) pruneVerifyRunStates(torrents []Torrent) {
+	defer c.verifyRunStates.access.Unlock()
+	c.verifyRunStates.access.Lock()
+	if len(c.verifyRunStates.running) == 0 {
+		return
+	}
+	for _, torrent := range torrents {
+		if torrent.ID == nil || torrent.Status == nil ||
+			*torrent.Status == TorrentStatusCheckWait || *torrent.Status == TorrentStatusCheck {
+			continue
+		}
+		if state, recorded := c.verifyRunStates.running[*torrent.ID]; recorded && time.Since(state.requested) >= verifyStartGrace {
+			delete(c.verifyRunStates.running, *torrent.ID)
+		}
+	}
+}
+
+// takeVerifyRunStates returns (and forgets) the ids which were running before their verification.
+func (c *Client) takeVerifyRunStates(ids []int64) (running []int64) {
+	defer c.verifyRunStates.access.Unlock()
+	c.verifyRunStates.access.Lock()
+	for _, id := range ids {
+		if c.verifyRunStates.running[id].running {
+			running = append(running, id)
+		}
+		delete(c.verifyRunStates.running, id)
+	}
+	return
+}
+
+// TorrentCancelVerify interrupts the verification of the given torrents. The RPC has no native cancel: this is
+// emulated by stopping the torrents being verified (or waiting to be), which halts the check, then starting again
+// the ones which were running when their verification was requested. As the RPC does not expose this state, it is
+// recorded by the client when it requests a verification: torrents whose verification was requested by another
+// client are left stopped. Torrents which are not being verified are left untouched.
+func (c *Client) TorrentCancelVerify(ctx context.Context, ids []int64) (err error) {
+	if len(ids) == 0 {
+		return errors.New("there must be at least one ID")
+	}
+	// Capture current state
+	torrents, err := c.fetchTorrents(ctx, []string{"id", "status"}, ids)
+	if err != nil {
+		return
+	}
+	checking := make([]int64, 0, len(torrents))
+	for _, torrent := range torrents {
+		if torrent.ID == nil || torrent.Status == nil {
+			continue
+		}
+		if *torrent.Status == TorrentStatusCheckWait || *torrent.Status == TorrentStatusCheck {
+			checking = append(checking, *torrent.ID)
+		}
+	}
+	if len(checking) == 0 {
+		return
+	}
+	// Interrupt and restore
+	if err = c.TorrentStopIDs(ctx, checking); err != nil {
+		return fmt.Errorf("can't stop the torrents being verified: %w", err)
+	}
+	running := c.takeVerifyRunStates(checking)
+	if len(running) == 0 {
+		return
+	}
+	if err = c.TorrentStartIDs(ctx, running); err != nil {
+		return fmt.Errorf("verification interrupted but can't start the torrents back: %w", err)
+	}
+	return
+}
+
+// torrentGetOne returns the requested fields of a single torrent, or a TorrentNotFoundError.
+func (c *Client) torrentGetOne(ctx context.Context, fields []string, id int64) (torrent Torrent, err error) {
+	torrents, err := c.fetchTorrents(ctx, fields, []int64{id})
+	if err != nil {
+		return
+	}
+	if len(torrents) != 1 {
+		err = TorrentNotFoundError{IDs: []int64{id}}
+		return
+	}
+	torrent = torrents[0]
+	return
+}
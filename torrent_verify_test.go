@@ -0,0 +1,98 @@
+package transmissionrpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestVerifyTorrentWaitsForTheCheck(t *testing.T) {
+	// Statuses reported by the successive torrent-get: the daemon is late to switch the torrent to check-wait
+	statuses := []TorrentStatus{TorrentStatusStopped, TorrentStatusStopped, TorrentStatusStopped, TorrentStatusCheck, TorrentStatusStopped}
+	var gets int
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		if rq.Method != MethodTorrentGet {
+			writeStubAnswer(t, w, rq, nil)
+			return
+		}
+		status, percentDone := statuses[len(statuses)-1], 1.0
+		if gets < len(statuses) {
+			status = statuses[gets]
+		}
+		if gets < len(statuses)-1 {
+			percentDone = 0.5 // stale value until the check is over
+		}
+		gets++
+		writeStubAnswer(t, w, rq, map[string]interface{}{"torrents": []map[string]interface{}{
+			{"id": 1, "status": status, "percentDone": percentDone, "corruptEver": 0},
+		}})
+	})
+	// initial state, the recorded run state, then the polls
+	statuses = append([]TorrentStatus{TorrentStatusStopped, TorrentStatusStopped}, statuses...)
+	valid, err := client.VerifyTorrent(context.Background(), 1, 10*time.Second)
+	if err != nil {
+		t.Fatalf("VerifyTorrent() failed: %v", err)
+	}
+	if !valid {
+		t.Error("verification result read before the check was over")
+	}
+	if gets != len(statuses) {
+		t.Errorf("got %d torrent-get, want %d (polling until the check is over)", gets, len(statuses))
+	}
+}
+
+func TestVerifyRunStateIsBestEffort(t *testing.T) {
+	var verifies int
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		switch rq.Method {
+		case MethodTorrentGet:
+			fmt.Fprintf(w, `{"arguments":{},"result":"daemon is busy","tag":%d}`, rq.Tag)
+		case MethodTorrentVerify:
+			verifies++
+			writeStubAnswer(t, w, rq, nil)
+		}
+	})
+	if err := client.TorrentVerifyIDs(context.Background(), []int64{1}); err != nil {
+		t.Fatalf("verify failed because of the run state recording: %v", err)
+	}
+	if verifies != 1 {
+		t.Errorf("got %d torrent-verify, want 1", verifies)
+	}
+}
+
+func TestVerifyRunStatesPruned(t *testing.T) {
+	client, err := New(mustParseURL(t, "http://127.0.0.1:1/transmission/rpc"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, seeding, checking := int64(1), TorrentStatusSeed, TorrentStatusCheck
+	client.verifyRunStates.running = map[int64]verifyRunState{
+		1: {running: true, requested: time.Now()},
+		2: {running: true, requested: time.Now().Add(-time.Minute)},
+		3: {running: true, requested: time.Now().Add(-time.Minute)},
+	}
+	other, third := int64(2), int64(3)
+	client.pruneVerifyRunStates([]Torrent{
+		{ID: &id, Status: &seeding},     // verification requested right now: not started yet
+		{ID: &other, Status: &seeding},  // verification over
+		{ID: &third, Status: &checking}, // still being verified
+	})
+	if _, kept := client.verifyRunStates.running[1]; !kept {
+		t.Error("state of a verification not started yet pruned")
+	}
+	if _, kept := client.verifyRunStates.running[2]; kept {
+		t.Error("state of a finished verification kept")
+	}
+	if _, kept := client.verifyRunStates.running[3]; !kept {
+		t.Error("state of a running verification pruned")
+	}
+	client.updateSessionID("first")
+	client.updateSessionID("second") // daemon restarted
+	if len(client.verifyRunStates.running) != 0 {
+		t.Error("states kept after a session id change")
+	}
+}
@@ -0,0 +1,735 @@
+// Package transmissionrpctest provides an in-memory fake Transmission RPC daemon, allowing to test the code using
+// a transmissionrpc client without a real daemon: torrents fixtures are seeded with AddTorrent() and the payloads
+// received can be asserted with Requests().
+package transmissionrpctest
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hekmon/transmissionrpc/v3"
+)
+
+/*
+	Fake daemon
+	httptest based, implementing the session id handshake and the torrents lifecycle
+    https://github.com/transmission/transmission/blob/4.0.3/docs/rpc-spec.md
+*/
+
+const (
+	csrfHeader = "X-Transmission-Session-Id"
+	rpcPath    = "/transmission/rpc"
+)
+
+// Torrent status codes, as sent by the daemon
+const (
+	statusStopped  = 0
+	statusVerify   = 2
+	statusDownload = 4
+	statusSeed     = 6
+)
+
+// Request is an RPC request received by the Server.
+type Request struct {
+	Method    string
+	Arguments json.RawMessage
+}
+
+// Decode unmarshals the request arguments into v (for example a transmissionrpc.TorrentSetPayload).
+func (r Request) Decode(v interface{}) error {
+	if len(r.Arguments) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Arguments, v)
+}
+
+// Server is an in-memory fake Transmission daemon. It must be created with NewServer() and closed with Close().
+// It is safe for concurrent use.
+type Server struct {
+	server    *httptest.Server
+	access    sync.Mutex
+	sessionID string
+	nextID    int64
+	torrents  []map[string]interface{} // wire form of the torrents, ordered by id
+	removed   []int64                  // since the last "recently-active" torrent-get
+	session   map[string]interface{}
+	requests  []Request
+	failures  map[string][]string
+}
+
+// NewServer starts a fake daemon with no torrents and default session values.
+func NewServer() (s *Server) {
+	s = &Server{
+		sessionID: newSessionID(),
+		nextID:    1,
+		session: map[string]interface{}{
+			"download-dir":        "/downloads",
+			"rpc-version":         transmissionrpc.RPCVersion,
+			"rpc-version-minimum": 14,
+			"rpc-version-semver":  "5.3.0",
+			"version":             "4.0.3 (transmissionrpctest)",
+		},
+		failures: make(map[string][]string),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL returns the RPC endpoint of the server, to be used with transmissionrpc.New().
+func (s *Server) URL() (endpoint *url.URL) {
+	endpoint, _ = url.Parse(s.server.URL + rpcPath) // httptest URLs are always valid
+	return
+}
+
+// Client returns a client connected to the server.
+func (s *Server) Client(extra *transmissionrpc.Config) (c *transmissionrpc.Client, err error) {
+	return transmissionrpc.New(s.URL(), extra)
+}
+
+// AddTorrent seeds a torrent fixture and returns its id. The id and hash are generated if not set, as well as a name
+// and a stopped status. Only the non nil fields are reported by torrent-get.
+func (s *Server) AddTorrent(torrent transmissionrpc.Torrent) (id int64, err error) {
+	fields, err := torrentFields(torrent)
+	if err != nil {
+		return
+	}
+	defer s.access.Unlock()
+	s.access.Lock()
+	id = s.addTorrent(fields)
+	return
+}
+
+// Torrent returns the current state of a torrent, as it would be decoded by a client.
+func (s *Server) Torrent(id int64) (torrent transmissionrpc.Torrent, found bool) {
+	defer s.access.Unlock()
+	s.access.Lock()
+	for _, fields := range s.torrents {
+		if fieldID(fields) == id {
+			return decodeTorrent(fields), true
+		}
+	}
+	return
+}
+
+// Torrents returns the current state of all the torrents, ordered by id.
+func (s *Server) Torrents() (torrents []transmissionrpc.Torrent) {
+	defer s.access.Unlock()
+	s.access.Lock()
+	torrents = make([]transmissionrpc.Torrent, len(s.torrents))
+	for index, fields := range s.torrents {
+		torrents[index] = decodeTorrent(fields)
+	}
+	return
+}
+
+// SetSession overrides the session values reported by session-get with the non nil fields of sessionArgs.
+func (s *Server) SetSession(sessionArgs transmissionrpc.SessionArguments) (err error) {
+	data, err := json.Marshal(sessionArgs)
+	if err != nil {
+		return
+	}
+	var fields map[string]interface{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return
+	}
+	defer s.access.Unlock()
+	s.access.Lock()
+	for key, value := range fields {
+		s.session[key] = value
+	}
+	return
+}
+
+// Requests returns the RPC requests received so far (the ones rejected by the session id handshake excluded),
+// optionally filtered on the given methods.
+func (s *Server) Requests(methods ...string) (requests []Request) {
+	defer s.access.Unlock()
+	s.access.Lock()
+	for _, request := range s.requests {
+		if len(methods) == 0 || containsString(methods, request.Method) {
+			requests = append(requests, request)
+		}
+	}
+	return
+}
+
+// ResetRequests forgets the requests received so far.
+func (s *Server) ResetRequests() {
+	defer s.access.Unlock()
+	s.access.Lock()
+	s.requests = nil
+}
+
+// FailNext makes the next request of method answer result instead of "success" (without any other effect).
+// Several failures of the same method are answered in order.
+func (s *Server) FailNext(method, result string) {
+	defer s.access.Unlock()
+	s.access.Lock()
+	s.failures[method] = append(s.failures[method], result)
+}
+
+// RotateSessionID changes the session id, as the daemon does on restart: the next request is answered 409.
+func (s *Server) RotateSessionID() {
+	defer s.access.Unlock()
+	s.access.Lock()
+	s.sessionID = newSessionID()
+}
+
+type requestPayload struct {
+	Method    string          `json:"method"`
+	Arguments json.RawMessage `json:"arguments"`
+	Tag       *int            `json:"tag"`
+}
+
+type answerPayload struct {
+	Arguments interface{} `json:"arguments"`
+	Result    string      `json:"result"`
+	Tag       *int        `json:"tag,omitempty"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	s.access.Lock()
+	defer s.access.Unlock()
+	// Session id handshake
+	if r.Header.Get(csrfHeader) != s.sessionID {
+		w.Header().Set(csrfHeader, s.sessionID)
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+	var request requestPayload
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.requests = append(s.requests, Request{Method: request.Method, Arguments: request.Arguments})
+	// Answer
+	answer := answerPayload{
+		Arguments: struct{}{},
+		Result:    "success",
+		Tag:       request.Tag,
+	}
+	if failures := s.failures[request.Method]; len(failures) > 0 {
+		answer.Result = failures[0]
+		s.failures[request.Method] = failures[1:]
+	} else if arguments, err := s.handle(request.Method, request.Arguments); err != nil {
+		answer.Result = err.Error()
+	} else if arguments != nil {
+		answer.Arguments = arguments
+	}
+	w.Header().Set(csrfHeader, s.sessionID)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(answer)
+}
+
+type selectorArguments struct {
+	IDs json.RawMessage `json:"ids"`
+}
+
+func (s *Server) handle(method string, raw json.RawMessage) (answer interface{}, err error) {
+	var selector selectorArguments
+	if len(raw) > 0 {
+		if err = json.Unmarshal(raw, &selector); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %v", err)
+		}
+	}
+	switch method {
+	case transmissionrpc.MethodTorrentGet:
+		return s.torrentGet(raw, selector.IDs)
+	case transmissionrpc.MethodTorrentSet:
+		return nil, s.torrentSet(raw, selector.IDs)
+	case transmissionrpc.MethodTorrentAdd:
+		return s.torrentAdd(raw)
+	case transmissionrpc.MethodTorrentRemove:
+		return nil, s.torrentRemove(selector.IDs)
+	case transmissionrpc.MethodTorrentStart, transmissionrpc.MethodTorrentStartNow:
+		return nil, s.setStatus(selector.IDs, statusDownload)
+	case transmissionrpc.MethodTorrentStop:
+		return nil, s.setStatus(selector.IDs, statusStopped)
+	case transmissionrpc.MethodTorrentVerify:
+		return nil, s.setStatus(selector.IDs, statusVerify)
+	case transmissionrpc.MethodTorrentSetLocation:
+		return nil, s.torrentSetLocation(raw, selector.IDs)
+	case transmissionrpc.MethodSessionGet:
+		return s.sessionGet(raw)
+	case transmissionrpc.MethodSessionSet:
+		return nil, s.sessionSet(raw)
+	case transmissionrpc.MethodSessionStats:
+		return s.sessionStats(), nil
+	case transmissionrpc.MethodFreeSpace:
+		var arguments struct {
+			Path string `json:"path"`
+		}
+		if err = json.Unmarshal(raw, &arguments); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %v", err)
+		}
+		return map[string]interface{}{"path": arguments.Path, "size-bytes": int64(1) << 40, "total_size": int64(1) << 41}, nil
+	case transmissionrpc.MethodPortTest:
+		return map[string]interface{}{"port-is-open": true}, nil
+	case transmissionrpc.MethodBlocklistUpdate:
+		return map[string]interface{}{"blocklist-size": 0}, nil
+	case transmissionrpc.MethodGroupGet:
+		return map[string]interface{}{"group": []interface{}{}}, nil
+	case transmissionrpc.MethodTorrentReannounce, transmissionrpc.MethodTorrentRenamePath,
+		transmissionrpc.MethodQueueMoveTop, transmissionrpc.MethodQueueMoveUp, transmissionrpc.MethodQueueMoveDown,
+		transmissionrpc.MethodQueueMoveBottom, transmissionrpc.MethodGroupSet, transmissionrpc.MethodSessionClose:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("method name not recognized")
+	}
+}
+
+// selectTorrents returns the torrents matching the ids argument (all of them if absent). recentlyActive is set
+// if the "recently-active" shortcut was used.
+func (s *Server) selectTorrents(ids json.RawMessage) (selected []map[string]interface{}, recentlyActive bool, err error) {
+	if len(ids) == 0 || string(ids) == "null" {
+		return s.torrents, false, nil
+	}
+	var values []interface{}
+	if ids[0] == '[' {
+		err = json.Unmarshal(ids, &values)
+	} else {
+		var value interface{}
+		err = json.Unmarshal(ids, &value)
+		if value == "recently-active" {
+			return s.torrents, true, nil
+		}
+		values = []interface{}{value}
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid ids: %v", err)
+	}
+	for _, torrent := range s.torrents {
+		for _, value := range values {
+			switch typed := value.(type) {
+			case float64:
+				if int64(typed) == fieldID(torrent) {
+					selected = append(selected, torrent)
+				}
+			case string:
+				if strings.EqualFold(typed, fieldString(torrent, "hashString")) {
+					selected = append(selected, torrent)
+				}
+			}
+		}
+	}
+	return
+}
+
+func (s *Server) torrentGet(raw, ids json.RawMessage) (answer interface{}, err error) {
+	var arguments struct {
+		Fields []string `json:"fields"`
+	}
+	if err = json.Unmarshal(raw, &arguments); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %v", err)
+	}
+	selected, recentlyActive, err := s.selectTorrents(ids)
+	if err != nil {
+		return
+	}
+	torrents := make([]map[string]interface{}, len(selected))
+	for index, torrent := range selected {
+		torrents[index] = make(map[string]interface{}, len(arguments.Fields))
+		for _, field := range arguments.Fields {
+			if value, found := torrent[field]; found {
+				torrents[index][field] = value
+			}
+		}
+	}
+	result := map[string]interface{}{"torrents": torrents}
+	if recentlyActive {
+		result["removed"] = append([]int64{}, s.removed...)
+		s.removed = nil
+	}
+	return result, nil
+}
+
+// torrentSetIgnored are the torrent-set arguments not reflected as is by torrent-get.
+var torrentSetIgnored = map[string]bool{
+	"ids": true, "files-wanted": true, "files-unwanted": true, "priority-high": true, "priority-low": true,
+	"priority-normal": true, "location": true, "trackerAdd": true, "trackerRemove": true, "trackerReplace": true,
+}
+
+func (s *Server) torrentSet(raw, ids json.RawMessage) (err error) {
+	var arguments map[string]interface{}
+	if err = json.Unmarshal(raw, &arguments); err != nil {
+		return fmt.Errorf("invalid arguments: %v", err)
+	}
+	selected, _, err := s.selectTorrents(ids)
+	if err != nil {
+		return
+	}
+	for _, torrent := range selected {
+		for key, value := range arguments {
+			if !torrentSetIgnored[key] {
+				torrent[key] = value
+			}
+		}
+		if location, ok := arguments["location"].(string); ok {
+			torrent["downloadDir"] = location
+		}
+		if trackerList, ok := arguments["trackerList"].(string); ok {
+			setTrackers(torrent, trackerList)
+		}
+	}
+	return
+}
+
+// setTrackers updates the trackers and trackerStats of a torrent from its new trackerList.
+func setTrackers(torrent map[string]interface{}, trackerList string) {
+	trackers := make([]interface{}, 0)
+	stats := make([]interface{}, 0)
+	tier, inTier := 0, false
+	for _, line := range strings.Split(trackerList, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			if inTier {
+				tier++
+				inTier = false
+			}
+			continue
+		}
+		inTier = true
+		host := line
+		if announce, err := url.Parse(line); err == nil && announce.Host != "" {
+			host = announce.Scheme + "://" + announce.Host
+		}
+		id := len(trackers)
+		trackers = append(trackers, map[string]interface{}{
+			"announce": line,
+			"id":       id,
+			"scrape":   "",
+			"sitename": "",
+			"tier":     tier,
+		})
+		epoch := time.Unix(0, 0)
+		stat := transmissionrpc.TrackerStats{
+			Announce:              line,
+			Host:                  host,
+			ID:                    int64(id),
+			Tier:                  int64(tier),
+			IsBackup:              tier > 0,
+			LastAnnounceStartTime: epoch,
+			LastAnnounceTime:      epoch,
+			LastScrapeStartTime:   epoch,
+			LastScrapeTime:        epoch,
+			NextAnnounceTime:      epoch,
+			NextScrapeTime:        epoch,
+		}
+		var fields map[string]interface{}
+		if data, err := json.Marshal(stat); err == nil && json.Unmarshal(data, &fields) == nil {
+			stats = append(stats, fields)
+		}
+	}
+	torrent["trackers"] = trackers
+	torrent["trackerStats"] = stats
+}
+
+func (s *Server) torrentAdd(raw json.RawMessage) (answer interface{}, err error) {
+	var arguments struct {
+		Filename    string   `json:"filename"`
+		MetaInfo    string   `json:"metainfo"`
+		DownloadDir *string  `json:"download-dir"`
+		Paused      bool     `json:"paused"`
+		Labels      []string `json:"labels"`
+	}
+	if err = json.Unmarshal(raw, &arguments); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %v", err)
+	}
+	if arguments.Filename == "" && arguments.MetaInfo == "" {
+		return nil, fmt.Errorf("no filename or metainfo specified")
+	}
+	// Identify the torrent
+	name, hash, err := torrentIdentity(arguments.Filename, arguments.MetaInfo)
+	if err != nil {
+		return
+	}
+	for _, torrent := range s.torrents {
+		if strings.EqualFold(fieldString(torrent, "hashString"), hash) {
+			return map[string]interface{}{"torrent-duplicate": torrentSummary(torrent)}, nil
+		}
+	}
+	// Add it
+	torrent := map[string]interface{}{
+		"name":        name,
+		"hashString":  hash,
+		"addedDate":   time.Now().Unix(),
+		"downloadDir": s.session["download-dir"],
+		"labels":      []string{},
+		"percentDone": 0,
+		"status":      statusDownload,
+	}
+	if arguments.DownloadDir != nil {
+		torrent["downloadDir"] = *arguments.DownloadDir
+	}
+	if arguments.Paused {
+		torrent["status"] = statusStopped
+	}
+	if arguments.Labels != nil {
+		torrent["labels"] = arguments.Labels
+	}
+	s.addTorrent(torrent)
+	return map[string]interface{}{"torrent-added": torrentSummary(torrent)}, nil
+}
+
+func (s *Server) torrentRemove(ids json.RawMessage) (err error) {
+	selected, _, err := s.selectTorrents(ids)
+	if err != nil {
+		return
+	}
+	removed := make(map[int64]bool, len(selected))
+	for _, torrent := range selected {
+		removed[fieldID(torrent)] = true
+	}
+	kept := make([]map[string]interface{}, 0, len(s.torrents))
+	for _, torrent := range s.torrents {
+		if id := fieldID(torrent); removed[id] {
+			s.removed = append(s.removed, id)
+		} else {
+			kept = append(kept, torrent)
+		}
+	}
+	s.torrents = kept
+	return
+}
+
+func (s *Server) setStatus(ids json.RawMessage, status int) (err error) {
+	selected, _, err := s.selectTorrents(ids)
+	if err != nil {
+		return
+	}
+	for _, torrent := range selected {
+		if done, _ := torrent["percentDone"].(float64); status == statusDownload && done >= 1 {
+			torrent["status"] = float64(statusSeed)
+		} else {
+			torrent["status"] = float64(status)
+		}
+	}
+	return
+}
+
+func (s *Server) torrentSetLocation(raw, ids json.RawMessage) (err error) {
+	var arguments struct {
+		Location string `json:"location"`
+	}
+	if err = json.Unmarshal(raw, &arguments); err != nil {
+		return fmt.Errorf("invalid arguments: %v", err)
+	}
+	selected, _, err := s.selectTorrents(ids)
+	if err != nil {
+		return
+	}
+	for _, torrent := range selected {
+		torrent["downloadDir"] = arguments.Location
+	}
+	return
+}
+
+func (s *Server) sessionGet(raw json.RawMessage) (answer interface{}, err error) {
+	var arguments struct {
+		Fields []string `json:"fields"`
+	}
+	if len(raw) > 0 {
+		if err = json.Unmarshal(raw, &arguments); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %v", err)
+		}
+	}
+	session := make(map[string]interface{}, len(s.session)+1)
+	for key, value := range s.session {
+		if len(arguments.Fields) == 0 || containsString(arguments.Fields, key) {
+			session[key] = value
+		}
+	}
+	if len(arguments.Fields) == 0 || containsString(arguments.Fields, "session-id") {
+		session["session-id"] = s.sessionID
+	}
+	return session, nil
+}
+
+func (s *Server) sessionSet(raw json.RawMessage) (err error) {
+	var arguments map[string]interface{}
+	if err = json.Unmarshal(raw, &arguments); err != nil {
+		return fmt.Errorf("invalid arguments: %v", err)
+	}
+	for key, value := range arguments {
+		s.session[key] = value
+	}
+	return
+}
+
+func (s *Server) sessionStats() (stats map[string]interface{}) {
+	active, paused := 0, 0
+	for _, torrent := range s.torrents {
+		if status, _ := torrent["status"].(float64); status == statusStopped {
+			paused++
+		} else {
+			active++
+		}
+	}
+	return map[string]interface{}{
+		"activeTorrentCount": active,
+		"pausedTorrentCount": paused,
+		"torrentCount":       len(s.torrents),
+		"downloadSpeed":      0,
+		"uploadSpeed":        0,
+	}
+}
+
+// addTorrent assigns the missing identity fields of a torrent, stores it and returns its id.
+func (s *Server) addTorrent(torrent map[string]interface{}) (id int64) {
+	if id = fieldID(torrent); id <= 0 {
+		id = s.nextID
+	}
+	if id >= s.nextID {
+		s.nextID = id + 1
+	}
+	torrent["id"] = float64(id)
+	if fieldString(torrent, "hashString") == "" {
+		torrent["hashString"] = newHash()
+	}
+	if fieldString(torrent, "name") == "" {
+		torrent["name"] = fmt.Sprintf("torrent %d", id)
+	}
+	if _, found := torrent["status"]; !found {
+		torrent["status"] = statusStopped
+	}
+	if trackerList, ok := torrent["trackerList"].(string); ok && torrent["trackers"] == nil {
+		setTrackers(torrent, trackerList)
+	}
+	// Normalize numbers the way JSON decoding does for the values set here
+	normalized, _ := torrentFieldsFromJSON(torrent)
+	for key, value := range normalized {
+		torrent[key] = value
+	}
+	s.torrents = append(s.torrents, torrent)
+	return
+}
+
+// torrentIdentity returns the name and info hash of an added torrent: from the metainfo, the magnet link or the local
+// .torrent file. Other filenames (URLs) can't be fetched by the fake: their hash is derived from the filename itself.
+func torrentIdentity(filename, metainfo string) (name, hash string, err error) {
+	if metainfo != "" {
+		if hash, err = transmissionrpc.InfoHashFromMetaInfo(metainfo); err != nil {
+			return "", "", errors.New("invalid or corrupt torrent file")
+		}
+		name = hash
+		return
+	}
+	if magnet, parseErr := url.Parse(filename); parseErr == nil && magnet.Scheme == "magnet" {
+		if hash, err = transmissionrpc.InfoHashFromMagnet(filename); err != nil {
+			return "", "", errors.New("invalid or corrupt torrent file")
+		}
+		if name = magnet.Query().Get("dn"); name == "" {
+			name = hash
+		}
+		return
+	}
+	name = strings.TrimSuffix(path.Base(filename), ".torrent")
+	if raw, readErr := os.ReadFile(filename); readErr == nil {
+		if hash, err = transmissionrpc.InfoHashFromTorrent(raw); err != nil {
+			return "", "", errors.New("invalid or corrupt torrent file")
+		}
+		return
+	}
+	sum := sha1.Sum([]byte(filename))
+	hash = hex.EncodeToString(sum[:])
+	return
+}
+
+func torrentSummary(torrent map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         torrent["id"],
+		"name":       torrent["name"],
+		"hashString": torrent["hashString"],
+	}
+}
+
+// torrentFields returns the wire form of the non nil fields of a torrent.
+func torrentFields(torrent transmissionrpc.Torrent) (fields map[string]interface{}, err error) {
+	data, err := json.Marshal(torrent)
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return
+	}
+	for key, value := range fields {
+		if value == nil {
+			delete(fields, key)
+		}
+	}
+	return
+}
+
+func torrentFieldsFromJSON(torrent map[string]interface{}) (fields map[string]interface{}, err error) {
+	data, err := json.Marshal(torrent)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &fields)
+	return
+}
+
+func decodeTorrent(fields map[string]interface{}) (torrent transmissionrpc.Torrent) {
+	data, _ := json.Marshal(fields) // values come from JSON decoding
+	_ = json.Unmarshal(data, &torrent)
+	return
+}
+
+func fieldID(torrent map[string]interface{}) int64 {
+	switch id := torrent["id"].(type) {
+	case float64:
+		return int64(id)
+	case int64:
+		return id
+	default:
+		return 0
+	}
+}
+
+func fieldString(torrent map[string]interface{}, field string) string {
+	value, _ := torrent[field].(string)
+	return value
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func newSessionID() string {
+	return hex.EncodeToString(randomBytes(24))
+}
+
+func newHash() string {
+	return hex.EncodeToString(randomBytes(sha1.Size))
+}
+
+func randomBytes(size int) []byte {
+	buf := make([]byte, size)
+	_, _ = rand.Read(buf) // crypto/rand does not fail on supported platforms
+	return buf
+}
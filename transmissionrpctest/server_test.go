@@ -0,0 +1,146 @@
+package transmissionrpctest_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hekmon/transmissionrpc/v3"
+	"github.com/hekmon/transmissionrpc/v3/transmissionrpctest"
+)
+
+// testMetainfo is a minimal single file .torrent content.
+const testMetainfo = "d8:announce32:https://tracker.example/announce4:infod6:lengthi1024e4:name8:file.bin12:piece lengthi16384e6:pieces20:aaaaaaaaaaaaaaaaaaaaee"
+
+func newTestServer(t *testing.T) (server *transmissionrpctest.Server, client *transmissionrpc.Client) {
+	t.Helper()
+	server = transmissionrpctest.NewServer()
+	t.Cleanup(server.Close)
+	client, err := server.Client(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func TestAddedTorrentHashes(t *testing.T) {
+	server, client := newTestServer(t)
+	ctx := context.Background()
+	wantHash, err := transmissionrpc.InfoHashFromTorrent([]byte(testMetainfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Metainfo
+	metainfo := base64.StdEncoding.EncodeToString([]byte(testMetainfo))
+	added, err := client.TorrentAdd(ctx, transmissionrpc.TorrentAddPayload{MetaInfo: &metainfo})
+	if err != nil {
+		t.Fatalf("metainfo add failed: %v", err)
+	}
+	if added.HashString == nil || *added.HashString != wantHash {
+		t.Errorf("got hash %v, want %s", added.HashString, wantHash)
+	}
+	// Same torrent from a local file: duplicate
+	file := filepath.Join(t.TempDir(), "file.torrent")
+	if err = os.WriteFile(file, []byte(testMetainfo), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	duplicate, err := client.TorrentAdd(ctx, transmissionrpc.TorrentAddPayload{Filename: &file})
+	if err != nil {
+		t.Fatalf("file add failed: %v", err)
+	}
+	if duplicate.ID == nil || added.ID == nil || *duplicate.ID != *added.ID {
+		t.Errorf("same torrent from a file got id %v, want the first one %v", duplicate.ID, added.ID)
+	}
+	// Magnet link with a base32 btih
+	magnet := "magnet:?xt=urn:btih:MFRGGZDFMZTWQ2LKNNWG23TPOBYXE43U&dn=letters"
+	added, err = client.TorrentAdd(ctx, transmissionrpc.TorrentAddPayload{Filename: &magnet})
+	if err != nil {
+		t.Fatalf("magnet add failed: %v", err)
+	}
+	if wantHash, _ = transmissionrpc.InfoHashFromMagnet(magnet); added.HashString == nil || *added.HashString != wantHash {
+		t.Errorf("got magnet hash %v, want %s", added.HashString, wantHash)
+	}
+	if count := len(server.Torrents()); count != 2 {
+		t.Errorf("got %d torrents, want 2", count)
+	}
+	// Corrupted metainfo
+	corrupted := base64.StdEncoding.EncodeToString([]byte("not a torrent"))
+	if _, err = client.TorrentAdd(ctx, transmissionrpc.TorrentAddPayload{MetaInfo: &corrupted}); err == nil {
+		t.Error("corrupted metainfo accepted")
+	}
+}
+
+func TestTorrentSetTrackerList(t *testing.T) {
+	server, client := newTestServer(t)
+	ctx := context.Background()
+	id, err := server.AddTorrent(transmissionrpc.Torrent{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tiers := [][]string{{"https://a.example/announce"}, {"https://b.example/announce", "udp://c.example:80"}}
+	if err = client.TorrentSet(ctx, transmissionrpc.TorrentSetPayload{
+		IDs:         []int64{id},
+		TrackerList: transmissionrpc.TrackerListFromTiers(tiers),
+	}); err != nil {
+		t.Fatalf("TorrentSet() failed: %v", err)
+	}
+	torrents, err := client.TorrentGet(ctx, []string{"trackers", "trackerStats", "trackerList"}, []int64{id})
+	if err != nil || len(torrents) != 1 {
+		t.Fatalf("TorrentGet() failed: %v", err)
+	}
+	torrent := torrents[0]
+	if got := torrent.TrackerTiers(); !reflect.DeepEqual(got, tiers) {
+		t.Errorf("got tiers %q from trackerList, want %q", got, tiers)
+	}
+	torrent.TrackerList = nil // use trackers
+	if got := torrent.TrackerTiers(); !reflect.DeepEqual(got, tiers) {
+		t.Errorf("got tiers %q from trackers, want %q", got, tiers)
+	}
+	if len(torrent.TrackerStats) != 3 || torrent.TrackerStats[2].Host != "udp://c.example:80" || torrent.TrackerStats[2].Tier != 1 {
+		t.Errorf("unexpected tracker stats: %+v", torrent.TrackerStats)
+	}
+}
+
+func TestActionsAndRequests(t *testing.T) {
+	server, client := newTestServer(t)
+	ctx := context.Background()
+	id, err := server.AddTorrent(transmissionrpc.Torrent{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.TorrentStartIDs(ctx, []int64{id}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if torrent, _ := server.Torrent(id); torrent.Status == nil || *torrent.Status == transmissionrpc.TorrentStatusStopped {
+		t.Errorf("torrent not started: %v", torrent.Status)
+	}
+	requests := server.Requests(transmissionrpc.MethodTorrentStart)
+	if len(requests) != 1 {
+		t.Fatalf("got %d torrent-start requests, want 1", len(requests))
+	}
+	var arguments struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err = requests[0].Decode(&arguments); err != nil || !reflect.DeepEqual(arguments.IDs, []int64{id}) {
+		t.Errorf("got torrent-start ids %v (%v), want [%d]", arguments.IDs, err, id)
+	}
+}
+
+func TestFailNextAndSessionRotation(t *testing.T) {
+	server, client := newTestServer(t)
+	ctx := context.Background()
+	server.FailNext(transmissionrpc.MethodSessionStats, "daemon is busy")
+	_, err := client.SessionStats(ctx)
+	var rpcErr transmissionrpc.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Result != "daemon is busy" {
+		t.Fatalf("got error %v, want the injected failure", err)
+	}
+	server.RotateSessionID()
+	if _, err = client.SessionStats(ctx); err != nil {
+		t.Fatalf("call after the session id rotation failed: %v", err)
+	}
+}
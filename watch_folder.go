@@ -0,0 +1,168 @@
+package transmissionrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+/*
+	Watch folder
+	Built on torrent-add
+*/
+
+const defaultWatchFolderPollInterval = 5 * time.Second
+
+// WatchFolderOptions configures WatchFolder.
+type WatchFolderOptions struct {
+	// PollInterval between two scans of the folder, defaults to 5 seconds.
+	PollInterval time.Duration
+	// Events (optional) triggers an immediate scan each time a path is received.
+	// It allows to plug a file system notification based watcher (fsnotify for example) without depending on it.
+	Events <-chan string
+	// DownloadDir (optional) of the added torrents, the daemon default download dir is used otherwise.
+	DownloadDir string
+	// Paused adds the torrents without starting them.
+	Paused bool
+	// DoneDir (optional): successfully added .torrent files are moved there instead of being deleted.
+	DoneDir string
+	// OnAdded (optional) is called for each torrent successfully added.
+	OnAdded func(path string, torrent Torrent)
+	// OnError (optional) is called when a .torrent file can't be added, moved or deleted.
+	// Files which failed to be added are not retried until they are modified.
+	OnError func(path string, err error)
+}
+
+type watchedFile struct {
+	size    int64
+	modTime time.Time
+	failed  bool
+}
+
+// WatchFolder watches dir for new .torrent files and adds them. A file is only added once its size and modification
+// time did not change between two scans, to avoid adding partially written files. Once added, the file is deleted (or
+// moved to DoneDir). WatchFolder blocks until ctx is cancelled and returns its error, or returns earlier if dir can't be read.
+func (c *Client) WatchFolder(ctx context.Context, dir string, opts WatchFolderOptions) (err error) {
+	// Validate
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultWatchFolderPollInterval
+	}
+	if _, err = os.ReadDir(dir); err != nil {
+		return fmt.Errorf("can't read watch folder: %w", err)
+	}
+	if opts.DoneDir != "" {
+		var info os.FileInfo
+		if info, err = os.Stat(opts.DoneDir); err != nil {
+			return fmt.Errorf("can't stat done folder: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("done folder '%s' is not a directory", opts.DoneDir)
+		}
+	}
+	// Watch
+	known := make(map[string]watchedFile)
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+	events := opts.Events
+	for {
+		c.scanWatchFolder(ctx, dir, opts, known)
+		select {
+		case <-ticker.C:
+		case _, ok := <-events:
+			if !ok {
+				// closed by the caller: only poll from now on
+				events = nil
+				continue
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *Client) scanWatchFolder(ctx context.Context, dir string, opts WatchFolderOptions, known map[string]watchedFile) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if opts.OnError != nil {
+			opts.OnError(dir, err)
+		}
+		return
+	}
+	present := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".torrent") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		present[path] = true
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		previous, seen := known[path]
+		current := watchedFile{size: info.Size(), modTime: info.ModTime()}
+		if !seen || previous.size != current.size || !previous.modTime.Equal(current.modTime) || current.size == 0 {
+			// new or still being written: wait for the next scan
+			known[path] = current
+			continue
+		}
+		if previous.failed {
+			continue
+		}
+		if err = c.addWatchedFile(ctx, path, opts); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			current.failed = true
+			known[path] = current
+			if opts.OnError != nil {
+				opts.OnError(path, err)
+			}
+			continue
+		}
+		delete(known, path)
+	}
+	// Forget files which are gone
+	for path := range known {
+		if !present[path] {
+			delete(known, path)
+		}
+	}
+}
+
+func (c *Client) addWatchedFile(ctx context.Context, path string, opts WatchFolderOptions) (err error) {
+	b64, err := File2Base64(path)
+	if err != nil {
+		return fmt.Errorf("can't encode '%s' content as base64: %w", path, err)
+	}
+	payload := TorrentAddPayload{MetaInfo: &b64}
+	if opts.DownloadDir != "" {
+		payload.DownloadDir = &opts.DownloadDir
+	}
+	if opts.Paused {
+		payload.Paused = &opts.Paused
+	}
+	torrent, err := c.TorrentAdd(ctx, payload)
+	if err != nil {
+		return
+	}
+	if opts.OnAdded != nil {
+		opts.OnAdded(path, torrent)
+	}
+	// Clean up the source file
+	if opts.DoneDir != "" {
+		err = os.Rename(path, filepath.Join(opts.DoneDir, filepath.Base(path)))
+	} else {
+		err = os.Remove(path)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		err = fmt.Errorf("torrent added but can't clean up the source file: %w", err)
+	} else {
+		err = nil
+	}
+	return
+}
@@ -0,0 +1,161 @@
+package transmissionrpc
+
+import (
+	"context"
+	"time"
+)
+
+/*
+	Watcher
+	Typed torrent events built on ChangeStream
+*/
+
+// WatchEventType is the kind of a WatchEvent.
+type WatchEventType int
+
+const (
+	// EventTorrentAdded is emitted when a torrent appears on the daemon
+	EventTorrentAdded WatchEventType = iota
+	// EventTorrentRemoved is emitted when a torrent disappears from the daemon (only ID is set)
+	EventTorrentRemoved
+	// EventTorrentCompleted is emitted when all the wanted data of a torrent has been downloaded
+	EventTorrentCompleted
+	// EventTorrentStatusChanged is emitted when the status of a torrent changes (see PreviousStatus)
+	EventTorrentStatusChanged
+	// EventTorrentStalled is emitted when a torrent becomes stalled (no transfer for a while)
+	EventTorrentStalled
+	// EventTorrentErrored is emitted when the daemon reports a new error for a torrent (see Torrent.TorrentError())
+	EventTorrentErrored
+	// EventPollError is emitted when polling the daemon failed (see Err), the watcher keeps polling
+	EventPollError
+)
+
+// watcherFields are always requested by the watcher.
+var watcherFields = []string{"id", "name", "hashString", "status", "leftUntilDone", "isStalled", "error", "errorString"}
+
+// WatchEvent is a torrent state change detected by a Watcher.
+type WatchEvent struct {
+	Type           WatchEventType
+	ID             int64
+	Torrent        Torrent       // latest values of the torrent (empty for EventTorrentRemoved and EventPollError)
+	PreviousStatus TorrentStatus // set for EventTorrentStatusChanged
+	Err            error         // set for EventPollError
+}
+
+// WatchOptions configures NewWatcher.
+type WatchOptions struct {
+	// Fields (optional) requested in addition to the ones needed to detect the events, reported in WatchEvent.Torrent.
+	Fields []string
+	// EmitInitial emits an EventTorrentAdded for each torrent present on the first poll. They are silently recorded otherwise.
+	EmitInitial bool
+	// Buffer is the size of the events channel. Polling is not blocked by a slow consumer: undelivered changes are merged.
+	Buffer int
+}
+
+// Watcher emits typed events for the torrents state changes, see Client.NewWatcher().
+type Watcher struct {
+	events chan WatchEvent
+}
+
+type watchedTorrent struct {
+	status   TorrentStatus
+	complete bool
+	stalled  bool
+	errored  int64
+}
+
+// NewWatcher starts polling the daemon every interval (with the "recently-active" shortcut after the first poll)
+// and emits the detected events on Watcher.Events() until ctx is cancelled. After a failed poll, a slow consumer or
+// a long interval, all the torrents are fetched again (see ChangeStream()): the events of the gap are still emitted.
+func (c *Client) NewWatcher(ctx context.Context, interval time.Duration, opts WatchOptions) (watcher *Watcher, err error) {
+	fields := watcherFields
+	for _, field := range opts.Fields {
+		fields = withTorrentField(fields, field)
+	}
+	batches, err := c.ChangeStream(ctx, fields, interval, true)
+	if err != nil {
+		return
+	}
+	if opts.Buffer < 0 {
+		opts.Buffer = 0
+	}
+	watcher = &Watcher{events: make(chan WatchEvent, opts.Buffer)}
+	go watcher.run(ctx, batches, opts.EmitInitial)
+	return
+}
+
+// Events returns the channel the events are sent on. It is closed once the watcher context is cancelled.
+func (w *Watcher) Events() <-chan WatchEvent {
+	return w.events
+}
+
+func (w *Watcher) run(ctx context.Context, batches <-chan ChangeBatch, emitInitial bool) {
+	defer close(w.events)
+	known := make(map[int64]watchedTorrent)
+	first := true
+	for batch := range batches {
+		var events []WatchEvent
+		for _, torrent := range batch.Changed {
+			events = append(events, diffWatchedTorrent(known, torrent, !first || emitInitial)...)
+		}
+		for _, id := range batch.Removed {
+			delete(known, id)
+			events = append(events, WatchEvent{Type: EventTorrentRemoved, ID: id})
+		}
+		if batch.Err != nil {
+			events = append(events, WatchEvent{Type: EventPollError, Err: batch.Err})
+		}
+		if batch.Err == nil || len(batch.Changed) > 0 || len(batch.Removed) > 0 {
+			first = false
+		}
+		for _, event := range events {
+			select {
+			case w.events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// diffWatchedTorrent records the new state of torrent and returns the events of its changes.
+func diffWatchedTorrent(known map[int64]watchedTorrent, torrent Torrent, emitAdded bool) (events []WatchEvent) {
+	if torrent.ID == nil {
+		return
+	}
+	current := watchedTorrent{
+		status:   deref(torrent.Status),
+		complete: torrent.LeftUntilDone != nil && *torrent.LeftUntilDone == 0,
+		stalled:  deref(torrent.IsStalled),
+		errored:  deref(torrent.Error),
+	}
+	previous, seen := known[*torrent.ID]
+	known[*torrent.ID] = current
+	event := WatchEvent{ID: *torrent.ID, Torrent: torrent}
+	if !seen {
+		if emitAdded {
+			event.Type = EventTorrentAdded
+			events = append(events, event)
+		}
+		return
+	}
+	if current.complete && !previous.complete {
+		event.Type = EventTorrentCompleted
+		events = append(events, event)
+	}
+	if current.status != previous.status {
+		event.Type = EventTorrentStatusChanged
+		event.PreviousStatus = previous.status
+		events = append(events, event)
+		event.PreviousStatus = 0
+	}
+	if current.stalled && !previous.stalled {
+		event.Type = EventTorrentStalled
+		events = append(events, event)
+	}
+	if current.errored != TorrentErrorNone && current.errored != previous.errored {
+		event.Type = EventTorrentErrored
+		events = append(events, event)
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package transmissionrpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatcherRemovalAfterFailedPoll(t *testing.T) {
+	var polls int32
+	client := newStubClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		rq := readStubRequest(t, r)
+		full := !strings.Contains(string(rq.Arguments), `"recently-active"`)
+		switch atomic.AddInt32(&polls, 1) {
+		case 1:
+			writeStubAnswer(t, w, rq, map[string]interface{}{"torrents": []map[string]interface{}{
+				{"id": 1, "status": 0}, {"id": 2, "status": 0},
+			}})
+		case 2:
+			// dropped poll: torrent 2 is removed meanwhile, out of the recently active window
+			fmt.Fprintf(w, `{"arguments":{},"result":"daemon is busy","tag":%d}`, rq.Tag)
+		default:
+			if !full {
+				writeStubAnswer(t, w, rq, map[string]interface{}{"torrents": []interface{}{}, "removed": []int64{}})
+				return
+			}
+			writeStubAnswer(t, w, rq, map[string]interface{}{"torrents": []map[string]interface{}{{"id": 1, "status": 0}}})
+		}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	watcher, err := client.NewWatcher(ctx, 10*time.Millisecond, WatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pollError bool
+	for event := range watcher.Events() {
+		switch event.Type {
+		case EventPollError:
+			pollError = true
+		case EventTorrentRemoved:
+			if event.ID != 2 {
+				t.Errorf("got removal of %d, want 2", event.ID)
+			}
+			if !pollError {
+				t.Error("removal reported before the poll error")
+			}
+			return
+		}
+	}
+	t.Fatal("removal of the torrent during the failed poll never reported")
+}